@@ -0,0 +1,86 @@
+package splunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response is read to find its
+// messages.
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned by Client methods when Splunk responds with a status
+// other than 2xx.  Use errors.As to inspect it:
+//
+//	var apiErr *splunk.APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+//		...
+//	}
+type APIError struct {
+	StatusCode int
+	// Status is the status line, e.g. "404 Not Found".
+	Status string
+	Method string
+	URL    string
+
+	// Messages are the messages Splunk returned in the body of the response.
+	Messages []RestMessage
+}
+
+func (e *APIError) Error() string {
+	result := "Status: " + e.Status + ":" + e.URL
+
+	texts := []string{}
+	for _, msg := range e.Messages {
+		texts = append(texts, msg.Type+": "+msg.Text)
+	}
+	if len(texts) > 0 {
+		result += ": " + strings.Join(texts, "; ")
+	}
+	return result
+}
+
+// checkResponse returns an *APIError if the response does not have a 2xx
+// status.  The body of an error response is consumed.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return newAPIError(resp, decodeErrorMessages(body))
+}
+
+func newAPIError(resp *http.Response, messages []RestMessage) *APIError {
+	result := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Messages:   messages,
+	}
+
+	if resp.Request != nil {
+		result.Method = resp.Request.Method
+		result.URL = resp.Request.URL.RequestURI()
+	}
+	return result
+}
+
+// decodeErrorMessages finds the messages in the body of an error response,
+// which is JSON with output_mode=json and XML otherwise.
+func decodeErrorMessages(body []byte) []RestMessage {
+	result := struct {
+		Messages []RestMessage `xml:"messages>msg" json:"messages"`
+	}{}
+
+	body = bytes.TrimSpace(body)
+	if bytes.HasPrefix(body, []byte("{")) {
+		json.Unmarshal(body, &result)
+	} else {
+		xml.Unmarshal(body, &result)
+	}
+	return result.Messages
+}
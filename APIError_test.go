@@ -0,0 +1,94 @@
+package splunk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorFromXML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response>
+  <messages>
+    <msg type="ERROR">Could not find object id=missing</msg>
+  </messages>
+</response>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	_, err := c.GetEntities([]string{"saved", "searches", "missing"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, received: %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != http.MethodGet ||
+		apiErr.URL != "/servicesNS/nobody/search/saved/searches/missing" {
+		t.Logf("Incorrect error details: %+v", apiErr)
+		t.Fail()
+	}
+
+	if len(apiErr.Messages) != 1 || apiErr.Messages[0].Type != "ERROR" ||
+		apiErr.Messages[0].Text != "Could not find object id=missing" {
+		t.Logf("Incorrect messages: %+v", apiErr.Messages)
+		t.Fail()
+	}
+
+	if !strings.Contains(err.Error(), "Could not find object id=missing") {
+		t.Logf("Message missing from error: %v", err)
+		t.Fail()
+	}
+}
+
+func TestAPIErrorFromJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"messages":[{"type":"ERROR","text":"You do not have permission."}]}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, OutputMode: OutputModeJSON}
+	err := c.DeleteEntity([]string{"services", "data", "indexes", "main"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, received: %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.Method != http.MethodDelete {
+		t.Logf("Incorrect error details: %+v", apiErr)
+		t.Fail()
+	}
+
+	if len(apiErr.Messages) != 1 || apiErr.Messages[0].Text != "You do not have permission." {
+		t.Logf("Incorrect messages: %+v", apiErr.Messages)
+		t.Fail()
+	}
+}
+
+func TestAPIErrorFromLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`<response><messages><msg code="incorrect_username_or_password" type="WARN">Login failed</msg></messages></response>`))
+	}))
+	defer server.Close()
+
+	_, err := NewClientFromLogin("admin", "wrong", "", "", server.URL, false)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, received: %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnauthorized || len(apiErr.Messages) != 1 ||
+		apiErr.Messages[0].Text != "Login failed" {
+		t.Logf("Incorrect error: %+v", apiErr)
+		t.Fail()
+	}
+}
@@ -0,0 +1,42 @@
+package splunk
+
+import "net/http"
+
+// Authenticator adds credentials to each request sent to Splunk.  Set a
+// Client's Authenticator to use something other than its session key.
+type Authenticator interface {
+	Authenticate(r *http.Request) error
+}
+
+// SessionKeyAuth authenticates with a session key from a login or a modular
+// input.  It is what a Client uses when its Authenticator is nil.
+type SessionKeyAuth string
+
+// Authenticate sets the Authorization header to Splunk <session key>.
+func (key SessionKeyAuth) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", "Splunk "+string(key))
+	return nil
+}
+
+// TokenAuth authenticates with a Splunk authentication token, such as one
+// created for a service account in Splunk Web.
+type TokenAuth string
+
+// Authenticate sets the Authorization header to Bearer <token>.
+func (token TokenAuth) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", "Bearer "+string(token))
+	return nil
+}
+
+// BasicAuth authenticates every request with a username and password using
+// HTTP basic authentication.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the Authorization header to the basic credentials.
+func (auth BasicAuth) Authenticate(r *http.Request) error {
+	r.SetBasicAuth(auth.Username, auth.Password)
+	return nil
+}
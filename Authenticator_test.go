@@ -0,0 +1,72 @@
+package splunk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticators(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	c := NewClientFromSessionKey("abc123", "", "", server.URL, false)
+	if _, err := c.GetEntities([]string{"services", "saved", "searches"}); err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+	if received.Header.Get("Authorization") != "Splunk abc123" {
+		t.Logf("Incorrect session key header: %v", received.Header.Get("Authorization"))
+		t.Fail()
+	}
+
+	c = NewClientFromToken("eyJraWQiOiJzcGx1bmsuc2VjcmV0In0", "", "", server.URL, false)
+	if _, err := c.GetEntities([]string{"services", "saved", "searches"}); err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+	if received.Header.Get("Authorization") != "Bearer eyJraWQiOiJzcGx1bmsuc2VjcmV0In0" {
+		t.Logf("Incorrect token header: %v", received.Header.Get("Authorization"))
+		t.Fail()
+	}
+
+	c = NewClientFromAuthenticator(BasicAuth{Username: "admin", Password: "changeme"},
+		"", "", server.URL, false)
+	if _, err := c.GetEntities([]string{"services", "saved", "searches"}); err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+	username, password, ok := received.BasicAuth()
+	if !ok || username != "admin" || password != "changeme" {
+		t.Logf("Incorrect basic credentials: %v %v %v", username, password, ok)
+		t.Fail()
+	}
+}
+
+type failingAuthenticator struct{}
+
+func (failingAuthenticator) Authenticate(r *http.Request) error {
+	return errors.New("No credentials available.")
+}
+
+func TestAuthenticatorError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	c := NewClientFromAuthenticator(failingAuthenticator{}, "", "", server.URL, false)
+	_, err := c.GetEntities([]string{"services", "saved", "searches"})
+	if err == nil || err.Error() != "No credentials available." {
+		t.Logf("Expected the authenticator's error, received: %v", err)
+		t.Fail()
+	}
+
+	if requests != 0 {
+		t.Logf("Request should not be sent when authentication fails: %v", requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,222 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultEntityPageSize is the number of entries requested per page when
+// iterating over entities without a Count.
+const DefaultEntityPageSize = 30
+
+// ListOptions control which entries are returned when listing entities.  Zero
+// values are not sent so Splunk's defaults apply.
+type ListOptions struct {
+	// Count is the maximum number of entries to return.
+	Count int
+	// Offset is the index of the first entry to return.
+	Offset int
+	// Search filters the entries, e.g. "name=foo*" or "disabled=0".
+	Search string
+	// SortKey is the field to sort by and SortDir is asc or desc.
+	SortKey string
+	SortDir string
+}
+
+func (opts *ListOptions) params() url.Values {
+	params := url.Values{}
+	if opts == nil {
+		return params
+	}
+
+	if opts.Count > 0 {
+		params.Set("count", strconv.Itoa(opts.Count))
+	}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if len(opts.Search) > 0 {
+		params.Set("search", opts.Search)
+	}
+	if len(opts.SortKey) > 0 {
+		params.Set("sort_key", opts.SortKey)
+	}
+	if len(opts.SortDir) > 0 {
+		params.Set("sort_dir", opts.SortDir)
+	}
+	return params
+}
+
+// GetEntitiesWithOptions returns a page of the entries at path.  opts may be
+// nil.
+func (c *Client) GetEntitiesWithOptions(path []string, opts *ListOptions) (*RestResponse, error) {
+	return c.GetEntitiesWithOptionsContext(context.Background(), path, opts)
+}
+
+// GetEntitiesWithOptionsContext is GetEntitiesWithOptions with a context that
+// cancels the request.
+func (c *Client) GetEntitiesWithOptionsContext(ctx context.Context, path []string,
+	opts *ListOptions) (*RestResponse, error) {
+	return c.entityRequestContext(ctx, http.MethodGet, path, opts.params())
+}
+
+// CreateEntity creates an entity by posting form encoded params to the
+// collection endpoint at path, e.g. []string{"services", "data", "indexes"},
+// and returns the new entity.
+func (c *Client) CreateEntity(path []string, params url.Values) (*RestEntry, error) {
+	return c.CreateEntityContext(context.Background(), path, params)
+}
+
+// CreateEntityContext is CreateEntity with a context that cancels the request.
+func (c *Client) CreateEntityContext(ctx context.Context, path []string,
+	params url.Values) (*RestEntry, error) {
+	return c.postEntity(ctx, path, params)
+}
+
+// UpdateEntity posts form encoded params to the entity at path and returns the
+// updated entity.  Settings that are not in params are left unchanged.
+func (c *Client) UpdateEntity(path []string, params url.Values) (*RestEntry, error) {
+	return c.UpdateEntityContext(context.Background(), path, params)
+}
+
+// UpdateEntityContext is UpdateEntity with a context that cancels the request.
+func (c *Client) UpdateEntityContext(ctx context.Context, path []string,
+	params url.Values) (*RestEntry, error) {
+	return c.postEntity(ctx, path, params)
+}
+
+// DeleteEntity deletes the entity at path.
+func (c *Client) DeleteEntity(path []string) error {
+	return c.DeleteEntityContext(context.Background(), path)
+}
+
+// DeleteEntityContext is DeleteEntity with a context that cancels the request.
+func (c *Client) DeleteEntityContext(ctx context.Context, path []string) error {
+	_, err := c.entityRequestContext(ctx, http.MethodDelete, path, nil)
+	return err
+}
+
+func (c *Client) postEntity(ctx context.Context, path []string,
+	params url.Values) (*RestEntry, error) {
+	resp, err := c.entityRequestContext(ctx, http.MethodPost, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Entries) == 0 {
+		return nil, errors.New("No entity returned.")
+	}
+	return &resp.Entries[0], nil
+}
+
+// GetAllEntities returns every entry at path, requesting as many pages as
+// needed.
+func (c *Client) GetAllEntities(path []string, opts *ListOptions) ([]RestEntry, error) {
+	return c.GetAllEntitiesContext(context.Background(), path, opts)
+}
+
+// GetAllEntitiesContext is GetAllEntities with a context that cancels the
+// requests.
+func (c *Client) GetAllEntitiesContext(ctx context.Context, path []string,
+	opts *ListOptions) ([]RestEntry, error) {
+	result := []RestEntry{}
+	it := c.IterateEntitiesContext(ctx, path, opts)
+	for it.Next() {
+		result = append(result, *it.Entry())
+	}
+	return result, it.Err()
+}
+
+// EntityIterator pages through the entries at a path until Splunk's
+// totalResults is reached.  Call Next to advance to each entry, Entry to read
+// it and Err once Next returns false.
+type EntityIterator struct {
+	client *Client
+	ctx    context.Context
+	path   []string
+	opts   ListOptions
+
+	page    *RestResponse
+	index   int
+	current *RestEntry
+	done    bool
+	err     error
+}
+
+// IterateEntities returns an iterator over every entry at path.  opts.Count is
+// used as the page size and opts.Offset as the first entry.  opts may be nil.
+func (c *Client) IterateEntities(path []string, opts *ListOptions) *EntityIterator {
+	return c.IterateEntitiesContext(context.Background(), path, opts)
+}
+
+// IterateEntitiesContext is IterateEntities with a context that cancels the
+// page requests.
+func (c *Client) IterateEntitiesContext(ctx context.Context, path []string,
+	opts *ListOptions) *EntityIterator {
+	it := &EntityIterator{client: c, ctx: ctx, path: path}
+	if opts != nil {
+		it.opts = *opts
+	}
+	if it.opts.Count <= 0 {
+		it.opts.Count = DefaultEntityPageSize
+	}
+	return it
+}
+
+// Next advances to the next entry, requesting another page when needed.  It
+// returns false when there are no more entries or a request fails.
+func (it *EntityIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.page == nil || it.index >= len(it.page.Entries) {
+		if it.done || !it.fetch() {
+			it.current = nil
+			return false
+		}
+	}
+
+	it.current = &it.page.Entries[it.index]
+	it.index++
+	return true
+}
+
+// Entry returns the current entry.
+func (it *EntityIterator) Entry() *RestEntry {
+	return it.current
+}
+
+// Response returns the most recent page, which has the feed's totalResults
+// and messages.
+func (it *EntityIterator) Response() *RestResponse {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EntityIterator) Err() error {
+	return it.err
+}
+
+// fetch requests the next page of entries and returns true if it has any.
+func (it *EntityIterator) fetch() bool {
+	page, err := it.client.GetEntitiesWithOptionsContext(it.ctx, it.path, &it.opts)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = page
+	it.index = 0
+	it.opts.Offset += len(page.Entries)
+
+	// Stop on a short page as well as at totalResults, in case the endpoint
+	// doesn't report a total.
+	it.done = len(page.Entries) < it.opts.Count ||
+		(page.TotalResults > 0 && it.opts.Offset >= page.TotalResults)
+
+	return len(page.Entries) > 0
+}
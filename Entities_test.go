@@ -0,0 +1,148 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestIterateEntities(t *testing.T) {
+	const total = 7
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		if r.URL.Query().Get("search") != "disabled=0" {
+			t.Errorf("Expected search to be sent: %v", r.URL.RawQuery)
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+		feed := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">` +
+			fmt.Sprintf("<opensearch:totalResults>%v</opensearch:totalResults>", total)
+		for i := offset; i < offset+count && i < total; i++ {
+			feed += fmt.Sprintf("<entry><title>app%v</title></entry>", i)
+		}
+		w.Write([]byte(feed + "</feed>"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	entries, err := c.GetAllEntities([]string{"services", "apps", "local"},
+		&ListOptions{Count: 3, Search: "disabled=0"})
+	if err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+
+	if len(entries) != total || entries[6].Title != "app6" {
+		t.Logf("Incorrect entries returned: %v", entries)
+		t.Fail()
+	}
+
+	if len(requests) != 3 {
+		t.Logf("Expected 3 pages to be requested: %v", requests)
+		t.Fail()
+	}
+
+	page, err := c.GetEntitiesWithOptions([]string{"services", "apps", "local"},
+		&ListOptions{Count: 2, Offset: 6, Search: "disabled=0"})
+	if err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+	if len(page.Entries) != 1 || page.TotalResults != total {
+		t.Logf("Incorrect page returned: %v %v", len(page.Entries), page.TotalResults)
+		t.Fail()
+	}
+}
+
+func TestEntityCreateUpdateDelete(t *testing.T) {
+	const indexesPath = "/servicesNS/nobody/search/data/indexes"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == indexesPath:
+			if r.PostForm.Get("name") != "fitness" {
+				t.Errorf("Expected name to be posted: %v", r.PostForm)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == indexesPath+"/fitness":
+			if r.PostForm.Get("frozenTimePeriodInSecs") != "86400" {
+				t.Errorf("Expected frozenTimePeriodInSecs to be posted: %v", r.PostForm)
+			}
+		case r.Method == http.MethodDelete && r.URL.Path == indexesPath+"/fitness":
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+			return
+		default:
+			t.Errorf("Unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">` +
+			`<entry><title>fitness</title><content><s:dict>` +
+			`<s:key name="frozenTimePeriodInSecs">86400</s:key>` +
+			`</s:dict></content></entry></feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+
+	entry, err := c.CreateEntity([]string{"data", "indexes"}, url.Values{"name": {"fitness"}})
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	if entry.Title != "fitness" {
+		t.Logf("Incorrect entity returned: %v", entry.Title)
+		t.Fail()
+	}
+
+	entry, err = c.UpdateEntity([]string{"data", "indexes", "fitness"},
+		url.Values{"frozenTimePeriodInSecs": {"86400"}})
+	if err != nil {
+		t.Fatalf("Failed to update entity: %v", err)
+	}
+	if entry.Contents.Get("frozenTimePeriodInSecs") != "86400" {
+		t.Logf("Incorrect entity returned: %v", entry.Contents.Map())
+		t.Fail()
+	}
+
+	err = c.DeleteEntity([]string{"data", "indexes", "fitness"})
+	if err != nil {
+		t.Fatalf("Failed to delete entity: %v", err)
+	}
+}
+
+func TestGetEntitiesContextCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{BaseURL: server.URL}
+	_, err := c.GetEntitiesContext(ctx, []string{"services", "saved", "searches"})
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected context.Canceled, received: %v", err)
+		t.Fail()
+	}
+
+	_, err = NewClientFromLoginContext(ctx, "admin", "changeme", "", "",
+		server.URL, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected login to be cancelled, received: %v", err)
+		t.Fail()
+	}
+
+	if requests != 0 {
+		t.Logf("Cancelled requests reached the server: %v", requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,135 @@
+package splunk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// allFiredAlerts is the group name Splunk uses for triggers of every saved
+// search.
+const allFiredAlerts = "-"
+
+// FiredAlertGroup is the triggered alerts for a single saved search.
+type FiredAlertGroup struct {
+	SavedSearchName string
+	Count           int
+}
+
+// FiredAlert is a single record of an alert being triggered.
+type FiredAlert struct {
+	// Name identifies the record and is used to delete it.
+	Name            string
+	SavedSearchName string
+	SID             string
+	Severity        int
+	TriggerTime     time.Time
+	AlertType       string
+	Actions         string
+	DigestMode      bool
+	Content         map[string]string
+}
+
+// ListFiredAlertGroups returns the number of triggered alerts for each saved
+// search that has fired.
+func (c *Client) ListFiredAlertGroups() ([]*FiredAlertGroup, error) {
+	return c.ListFiredAlertGroupsContext(context.Background())
+}
+
+// ListFiredAlertGroupsContext is ListFiredAlertGroups with a context that
+// cancels the request.
+func (c *Client) ListFiredAlertGroupsContext(ctx context.Context) ([]*FiredAlertGroup, error) {
+	params := url.Values{}
+	params.Set("count", "0")
+
+	resp, err := c.entityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("alerts", "fired_alerts"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*FiredAlertGroup{}
+	for _, entry := range resp.Entries {
+		if entry.Title == allFiredAlerts {
+			continue
+		}
+
+		content := entry.Contents.Map()
+		count, _ := strconv.Atoi(content["triggered_alert_count"])
+		result = append(result, &FiredAlertGroup{
+			SavedSearchName: entry.Title,
+			Count:           count,
+		})
+	}
+	return result, nil
+}
+
+// ListFiredAlerts returns the trigger records for a saved search.  If
+// savedSearchName is empty the records for every saved search are returned.
+func (c *Client) ListFiredAlerts(savedSearchName string) ([]*FiredAlert, error) {
+	return c.ListFiredAlertsContext(context.Background(), savedSearchName)
+}
+
+// ListFiredAlertsContext is ListFiredAlerts with a context that cancels the
+// request.
+func (c *Client) ListFiredAlertsContext(ctx context.Context,
+	savedSearchName string) ([]*FiredAlert, error) {
+
+	if len(savedSearchName) == 0 {
+		savedSearchName = allFiredAlerts
+	}
+
+	params := url.Values{}
+	params.Set("count", "0")
+
+	resp, err := c.namedEntityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("alerts", "fired_alerts"), savedSearchName, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*FiredAlert{}
+	for _, entry := range resp.Entries {
+		result = append(result, newFiredAlertFromEntry(entry))
+	}
+	return result, nil
+}
+
+// DeleteFiredAlert deletes a trigger record by its name.
+func (c *Client) DeleteFiredAlert(name string) error {
+	return c.DeleteFiredAlertContext(context.Background(), name)
+}
+
+// DeleteFiredAlertContext is DeleteFiredAlert with a context that cancels the
+// request.
+func (c *Client) DeleteFiredAlertContext(ctx context.Context, name string) error {
+	_, err := c.namedEntityRequestContext(ctx, http.MethodDelete,
+		c.servicesPath("alerts", "fired_alerts"), name, nil)
+	return err
+}
+
+func newFiredAlertFromEntry(entry RestEntry) *FiredAlert {
+	content := entry.Contents.Map()
+	severity, _ := strconv.Atoi(content["severity"])
+
+	result := &FiredAlert{
+		Name:            entry.Title,
+		SavedSearchName: content["savedsearch_name"],
+		SID:             content["sid"],
+		Severity:        severity,
+		AlertType:       content["alert_type"],
+		Actions:         content["actions"],
+		DigestMode:      parseSplunkBool(content["digest_mode"]),
+		Content:         content,
+	}
+
+	triggerTime, err := strconv.ParseFloat(strings.TrimSpace(content["trigger_time"]), 64)
+	if err == nil {
+		result.TriggerTime = time.Unix(int64(triggerTime), 0)
+	}
+
+	return result
+}
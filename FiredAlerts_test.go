@@ -0,0 +1,130 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const firedAlertGroupsResponse string = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">
+  <title>alerts</title>
+  <entry>
+    <title>-</title>
+    <content type="text/xml">
+      <s:dict><s:key name="triggered_alert_count">3</s:key></s:dict>
+    </content>
+  </entry>
+  <entry>
+    <title>Errors in the last hour</title>
+    <content type="text/xml">
+      <s:dict><s:key name="triggered_alert_count">3</s:key></s:dict>
+    </content>
+  </entry>
+</feed>`
+
+const firedAlertsResponse string = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">
+  <title>Errors in the last hour</title>
+  <entry>
+    <title>scheduler__admin__search__RMD5e461_at_1470836700_12</title>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="actions">email</s:key>
+        <s:key name="alert_type">number of events</s:key>
+        <s:key name="digest_mode">1</s:key>
+        <s:key name="savedsearch_name">Errors in the last hour</s:key>
+        <s:key name="severity">4</s:key>
+        <s:key name="sid">scheduler__admin__search__RMD5e461_at_1470836700_12</s:key>
+        <s:key name="trigger_time">1470836703</s:key>
+      </s:dict>
+    </content>
+  </entry>
+</feed>`
+
+func TestListFiredAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/alerts/fired_alerts":
+			w.Write([]byte(firedAlertGroupsResponse))
+		case "/services/alerts/fired_alerts/-":
+			w.Write([]byte(firedAlertsResponse))
+		default:
+			t.Errorf("Incorrect path requested: %v", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+
+	groups, err := c.ListFiredAlertGroups()
+	if err != nil {
+		t.Fatalf("Failed to list fired alert groups: %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].SavedSearchName != "Errors in the last hour" ||
+		groups[0].Count != 3 {
+		t.Logf("Incorrect fired alert groups returned: %v", groups)
+		t.Fail()
+	}
+
+	alerts, err := c.ListFiredAlerts("")
+	if err != nil {
+		t.Fatalf("Failed to list fired alerts: %v", err)
+	}
+
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 fired alert, received: %v", len(alerts))
+	}
+
+	alert := alerts[0]
+	if alert.SID != "scheduler__admin__search__RMD5e461_at_1470836700_12" ||
+		alert.Severity != 4 || !alert.DigestMode {
+		t.Logf("Incorrect fired alert returned: %v", alert)
+		t.Fail()
+	}
+
+	if alert.TriggerTime.Unix() != 1470836703 {
+		t.Logf("Incorrect trigger time. Expected: 1470836703 Received: %v",
+			alert.TriggerTime.Unix())
+		t.Fail()
+	}
+}
+
+func TestListFiredAlertsEscaping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/services/alerts/fired_alerts/Errors%2Fhour" {
+			t.Errorf("Incorrect path requested: %v", r.URL.EscapedPath())
+		}
+		w.Write([]byte(firedAlertsResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	if _, err := c.ListFiredAlerts("Errors/hour"); err != nil {
+		t.Fatalf("Failed to list fired alerts: %v", err)
+	}
+}
+
+func TestDeleteFiredAlert(t *testing.T) {
+	requested := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.Method + " " + r.URL.EscapedPath()
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	err := c.DeleteFiredAlert("scheduler__admin__search__RMD5e461_at_1470836700_12/1")
+	if err != nil {
+		t.Fatalf("Failed to delete fired alert: %v", err)
+	}
+
+	expected := "DELETE /servicesNS/nobody/search/alerts/fired_alerts/" +
+		"scheduler__admin__search__RMD5e461_at_1470836700_12%2F1"
+	if requested != expected {
+		t.Logf("Incorrect request.\nExpected: %v\nReceived: %v", expected, requested)
+		t.Fail()
+	}
+}
@@ -0,0 +1,120 @@
+package splunk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPOptions configure the *http.Client that a Client creates when its
+// HTTPClient is nil.  Zero values keep the defaults of http.DefaultTransport.
+type HTTPOptions struct {
+	// Timeout limits the time taken by each request, including reading the
+	// response body.  Zero means no timeout.
+	Timeout time.Duration
+
+	// MaxIdleConns and MaxIdleConnsPerHost size the pool of idle connections
+	// kept for reuse, and IdleConnTimeout is how long they are kept.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// TLSHandshakeTimeout limits the time taken by the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+
+	// RootCAs are the certificate authorities trusted to sign the server's
+	// certificate, e.g. Splunk's cacert.pem loaded with LoadCABundle.  The
+	// system roots are used if it is nil.  Certificates are only verified when
+	// the Client's ValidateTLS is true.
+	RootCAs *x509.CertPool
+
+	// ServerName is the name the server's certificate is verified against when
+	// it differs from the host in BaseURL, e.g. when connecting by IP address.
+	// Splunk's default certificates are issued to SplunkServerDefaultCert.
+	ServerName string
+
+	// Certificates are presented to the server for mutual TLS, e.g. from
+	// tls.LoadX509KeyPair.
+	Certificates []tls.Certificate
+
+	// Proxy returns the proxy for a request, e.g. http.ProxyURL(u).  The
+	// environment's HTTPS_PROXY and NO_PROXY are used if it is nil.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// RoundTripper replaces the transport completely, e.g. to add logging or
+	// metrics.  ValidateTLS and the other transport options are not applied to
+	// it.
+	RoundTripper http.RoundTripper
+}
+
+// httpClient returns the *http.Client used for every request.  It is
+// HTTPClient if one was given, otherwise a client is built from HTTPOptions
+// and ValidateTLS on first use and kept so that connections are reused.
+// Changes to HTTPOptions or ValidateTLS after the first request have no effect
+// unless HTTPClient is set to nil again.
+func (c *Client) httpClient() *http.Client {
+	c.httpMu.Lock()
+	defer c.httpMu.Unlock()
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = c.newSplunkHttpClient()
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) newSplunkHttpClient() *http.Client {
+	opts := c.HTTPOptions
+	if opts.RoundTripper != nil {
+		return &http.Client{Transport: opts.RoundTripper, Timeout: opts.Timeout}
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != nil {
+		tr.Proxy = opts.Proxy
+	}
+	if opts.MaxIdleConns > 0 {
+		tr.MaxIdleConns = opts.MaxIdleConns
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.TLSHandshakeTimeout > 0 {
+		tr.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+
+	tr.TLSClientConfig = &tls.Config{
+		RootCAs:      opts.RootCAs,
+		ServerName:   opts.ServerName,
+		Certificates: opts.Certificates,
+	}
+
+	//Splunk ships with self signed certificates and these run on a lot of instances
+	// this makes it really hard to do certificate validation
+	if !c.ValidateTLS {
+		tr.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	return &http.Client{Transport: tr, Timeout: opts.Timeout}
+}
+
+// LoadCABundle reads PEM encoded certificates from a file, such as
+// $SPLUNK_HOME/etc/auth/cacert.pem, for use as HTTPOptions.RootCAs.
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.New("No certificates found in " + path + ".")
+	}
+	return pool, nil
+}
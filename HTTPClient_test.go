@@ -0,0 +1,219 @@
+package splunk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	for i := 0; i < 5; i++ {
+		_, err := c.GetEntities([]string{"services", "saved", "searches"})
+		if err != nil {
+			t.Fatalf("Failed to get entities: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 1 {
+		t.Logf("Expected one connection to be reused, opened: %v", connections)
+		t.Fail()
+	}
+}
+
+func TestHTTPClientValidateTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	_, err := c.GetEntities([]string{"services", "saved", "searches"})
+	if err != nil {
+		t.Logf("Self signed certificate should be accepted without ValidateTLS: %v", err)
+		t.Fail()
+	}
+
+	c = &Client{BaseURL: server.URL, ValidateTLS: true}
+	_, err = c.GetEntities([]string{"services", "saved", "searches"})
+	if err == nil {
+		t.Log("Self signed certificate should be rejected with ValidateTLS.")
+		t.Fail()
+	}
+}
+
+type countingRoundTripper struct {
+	requests int
+}
+
+func (rt *countingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestHTTPOptionsRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	rt := &countingRoundTripper{}
+	c := &Client{
+		BaseURL:     server.URL,
+		HTTPOptions: HTTPOptions{RoundTripper: rt, Timeout: time.Second},
+	}
+
+	_, err := c.GetEntities([]string{"services", "saved", "searches"})
+	if err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+
+	if rt.requests != 1 {
+		t.Logf("Expected the round tripper to send the request, sent: %v", rt.requests)
+		t.Fail()
+	}
+	if c.HTTPClient == nil || c.HTTPClient.Timeout != time.Second {
+		t.Logf("HTTP client was not configured from options: %+v", c.HTTPClient)
+		t.Fail()
+	}
+}
+
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	path := filepath.Join(t.TempDir(), "cacert.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	return path
+}
+
+func TestHTTPOptionsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	pool, err := LoadCABundle(writeServerCA(t, server))
+	if err != nil {
+		t.Fatalf("Failed to load CA bundle: %v", err)
+	}
+
+	c := &Client{BaseURL: server.URL, ValidateTLS: true,
+		HTTPOptions: HTTPOptions{RootCAs: pool}}
+	_, err = c.GetEntities([]string{"services", "saved", "searches"})
+	if err != nil {
+		t.Logf("Certificate signed by the CA bundle should be accepted: %v", err)
+		t.Fail()
+	}
+
+	// The test server's certificate is issued to example.com as well as
+	// 127.0.0.1.
+	c = &Client{BaseURL: server.URL, ValidateTLS: true,
+		HTTPOptions: HTTPOptions{RootCAs: pool, ServerName: "example.com"}}
+	_, err = c.GetEntities([]string{"services", "saved", "searches"})
+	if err != nil {
+		t.Logf("Certificate should be accepted for the server name override: %v", err)
+		t.Fail()
+	}
+
+	c = &Client{BaseURL: server.URL, ValidateTLS: true,
+		HTTPOptions: HTTPOptions{RootCAs: pool, ServerName: "splunk.example.org"}}
+	_, err = c.GetEntities([]string{"services", "saved", "searches"})
+	if err == nil {
+		t.Log("Certificate should be rejected for a different server name.")
+		t.Fail()
+	}
+}
+
+func TestLoadCABundleWithoutCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := ioutil.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+
+	_, err := LoadCABundle(path)
+	if err == nil {
+		t.Log("Expected an error for a file without certificates.")
+		t.Fail()
+	}
+}
+
+func TestHTTPOptionsClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 ||
+			r.TLS.PeerCertificates[0].Subject.CommonName != "kvtransfer" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	_, err := c.GetEntities([]string{"services", "saved", "searches"})
+	if err == nil {
+		t.Log("Request without a client certificate should fail.")
+		t.Fail()
+	}
+
+	c = &Client{BaseURL: server.URL,
+		HTTPOptions: HTTPOptions{Certificates: []tls.Certificate{newClientCertificate(t)}}}
+	_, err = c.GetEntities([]string{"services", "saved", "searches"})
+	if err != nil {
+		t.Logf("Request with a client certificate failed: %v", err)
+		t.Fail()
+	}
+}
+
+func newClientCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kvtransfer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
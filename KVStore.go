@@ -0,0 +1,172 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// KVStoreInsert adds record to a KV Store collection and returns the _key that
+// Splunk generated for it.
+func (c *Client) KVStoreInsert(collection string, record interface{}) (string, error) {
+	return c.KVStoreInsertContext(context.Background(), collection, record)
+}
+
+// KVStoreInsertContext is KVStoreInsert with a context that cancels the
+// request.
+func (c *Client) KVStoreInsertContext(ctx context.Context, collection string,
+	record interface{}) (string, error) {
+	result := struct {
+		Key string `json:"_key"`
+	}{}
+
+	err := c.kvStoreRequestContext(ctx, http.MethodPost,
+		[]string{"storage", "collections", "data", collection},
+		nil, record, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Key, nil
+}
+
+// KVStoreGet decodes the record with the given key into the value pointed to by
+// into.
+func (c *Client) KVStoreGet(collection, key string, into interface{}) error {
+	return c.KVStoreGetContext(context.Background(), collection, key, into)
+}
+
+// KVStoreGetContext is KVStoreGet with a context that cancels the request.
+func (c *Client) KVStoreGetContext(ctx context.Context, collection, key string,
+	into interface{}) error {
+	return c.kvStoreRecordRequestContext(ctx, http.MethodGet, collection, key,
+		nil, into)
+}
+
+// KVStoreDelete deletes the record with the given key from a collection.
+func (c *Client) KVStoreDelete(collection, key string) error {
+	return c.KVStoreDeleteContext(context.Background(), collection, key)
+}
+
+// KVStoreDeleteContext is KVStoreDelete with a context that cancels the
+// request.
+func (c *Client) KVStoreDeleteContext(ctx context.Context, collection, key string) error {
+	return c.kvStoreRecordRequestContext(ctx, http.MethodDelete, collection, key,
+		nil, nil)
+}
+
+// KVStoreDeleteQuery deletes the records in a collection that match query,
+// which is encoded as JSON, e.g. KVEq("name", "bob").  A nil query deletes
+// every record in the collection.
+func (c *Client) KVStoreDeleteQuery(collection string, query interface{}) error {
+	return c.KVStoreDeleteQueryContext(context.Background(), collection, query)
+}
+
+// KVStoreDeleteQueryContext is KVStoreDeleteQuery with a context that cancels
+// the request.
+func (c *Client) KVStoreDeleteQueryContext(ctx context.Context, collection string,
+	query interface{}) error {
+	params := url.Values{}
+	if query != nil {
+		b, err := json.Marshal(query)
+		if err != nil {
+			return err
+		}
+		params.Set("query", string(b))
+	}
+
+	return c.kvStoreRequestContext(ctx, http.MethodDelete,
+		[]string{"storage", "collections", "data", collection},
+		params, nil, nil)
+}
+
+// KVStoreBatchSave inserts or updates many records in a single request.
+// records must encode to a JSON array.  Records with a _key that already exists
+// are updated.  The keys of the saved records are returned in order.
+func (c *Client) KVStoreBatchSave(collection string, records interface{}) ([]string, error) {
+	return c.KVStoreBatchSaveContext(context.Background(), collection, records)
+}
+
+// KVStoreBatchSaveContext is KVStoreBatchSave with a context that cancels the
+// request.
+func (c *Client) KVStoreBatchSaveContext(ctx context.Context, collection string,
+	records interface{}) ([]string, error) {
+	result := []string{}
+	err := c.kvStoreRequestContext(ctx, http.MethodPost,
+		[]string{"storage", "collections", "data", collection, "batch_save"},
+		nil, records, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// kvStoreRequestContext sends payload encoded as JSON to a KV Store endpoint
+// and decodes the JSON response into into.  payload and into may be nil.  The
+// context cancels the request.
+func (c *Client) kvStoreRequestContext(ctx context.Context, method string,
+	path []string, params url.Values, payload interface{}, into interface{}) error {
+
+	u, err := c.buildRequestPath(c.servicesPath(path...))
+	if err != nil {
+		return err
+	}
+
+	return c.kvStoreURLRequestContext(ctx, method, u, params, payload, into)
+}
+
+// kvStoreRecordRequestContext is kvStoreRequestContext for the record with the
+// given key, which is escaped because keys such as email addresses or URLs may
+// contain a /.
+func (c *Client) kvStoreRecordRequestContext(ctx context.Context, method string,
+	collection, key string, payload interface{}, into interface{}) error {
+
+	u, err := c.buildEntityPath(
+		c.servicesPath("storage", "collections", "data", collection), key)
+	if err != nil {
+		return err
+	}
+
+	return c.kvStoreURLRequestContext(ctx, method, u, nil, payload, into)
+}
+
+// kvStoreURLRequestContext sends the request for kvStoreRequestContext to the
+// KV Store endpoint at u.
+func (c *Client) kvStoreURLRequestContext(ctx context.Context, method string,
+	u *url.URL, params url.Values, payload interface{}, into interface{}) error {
+
+	if len(params) > 0 {
+		u.RawQuery = params.Encode()
+	}
+
+	var body []byte
+	contentType := ""
+	if payload != nil {
+		//Encode the payload into JSON
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		contentType = "application/json"
+	}
+
+	resp, err := c.sendRequestContext(ctx, method, u, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = checkResponse(resp); err != nil {
+		return err
+	}
+
+	if into == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	return decoder.Decode(into)
+}
@@ -0,0 +1,207 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type KVStoreFieldType string
+
+const KVStoreFieldArray = "array"
+const KVStoreFieldNumber = "number"
+const KVStoreFieldBool = "bool"
+const KVStoreFieldString = "string"
+const KVStoreFieldCIDR = "cidr"
+const KVStoreFieldTime = "time"
+
+// KVStoreCollectionSchema describes a KV Store collection as configured in
+// collections.conf.
+type KVStoreCollectionSchema struct {
+	Name string
+
+	// Fields maps field names to their types.
+	Fields map[string]KVStoreFieldType
+	// AcceleratedFields maps the name of an acceleration to the JSON that
+	// defines it, e.g. {"name": 1}.
+	AcceleratedFields map[string]string
+	// EnforceTypes rejects records with values that don't match Fields.
+	EnforceTypes bool
+
+	// ID is the URL of the collection returned by Splunk, which includes the
+	// app that owns it.
+	ID string
+}
+
+// ListKVStoreCollections returns the schema of every KV Store collection
+// visible to the client.
+func (c *Client) ListKVStoreCollections() ([]*KVStoreCollectionSchema, error) {
+	return c.ListKVStoreCollectionsContext(context.Background())
+}
+
+// ListKVStoreCollectionsContext is ListKVStoreCollections with a context that
+// cancels the request.
+func (c *Client) ListKVStoreCollectionsContext(ctx context.Context) ([]*KVStoreCollectionSchema, error) {
+	params := url.Values{}
+	params.Set("count", "0")
+
+	resp, err := c.entityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("storage", "collections", "config"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*KVStoreCollectionSchema{}
+	for _, entry := range resp.Entries {
+		result = append(result, newKVStoreCollectionSchemaFromEntry(entry))
+	}
+	return result, nil
+}
+
+// CreateKVStoreCollection creates a collection and sets its schema.
+func (c *Client) CreateKVStoreCollection(schema *KVStoreCollectionSchema) error {
+	return c.CreateKVStoreCollectionContext(context.Background(), schema)
+}
+
+// CreateKVStoreCollectionContext is CreateKVStoreCollection with a context that
+// cancels the requests.
+func (c *Client) CreateKVStoreCollectionContext(ctx context.Context,
+	schema *KVStoreCollectionSchema) error {
+	if len(schema.Name) == 0 {
+		return errors.New("KV Store collection must have a name.")
+	}
+
+	params := url.Values{}
+	params.Set("name", schema.Name)
+
+	_, err := c.entityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("storage", "collections", "config"), params)
+	if err != nil {
+		return err
+	}
+
+	return c.UpdateKVStoreCollectionContext(ctx, schema)
+}
+
+// UpdateKVStoreCollection sets the field types, accelerated fields and type
+// enforcement of an existing collection.  Fields that are not in the schema
+// are left unchanged.
+func (c *Client) UpdateKVStoreCollection(schema *KVStoreCollectionSchema) error {
+	return c.UpdateKVStoreCollectionContext(context.Background(), schema)
+}
+
+// UpdateKVStoreCollectionContext is UpdateKVStoreCollection with a context that
+// cancels the request.
+func (c *Client) UpdateKVStoreCollectionContext(ctx context.Context,
+	schema *KVStoreCollectionSchema) error {
+	_, err := c.entityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("storage", "collections", "config", schema.Name),
+		schema.params())
+	return err
+}
+
+// DeleteKVStoreCollection deletes a collection and all of its records.
+func (c *Client) DeleteKVStoreCollection(name string) error {
+	return c.DeleteKVStoreCollectionContext(context.Background(), name)
+}
+
+// DeleteKVStoreCollectionContext is DeleteKVStoreCollection with a context that
+// cancels the request.
+func (c *Client) DeleteKVStoreCollectionContext(ctx context.Context, name string) error {
+	_, err := c.entityRequestContext(ctx, http.MethodDelete,
+		c.servicesPath("storage", "collections", "config", name), nil)
+	return err
+}
+
+// EnsureKVStoreCollection creates the collection if it doesn't exist and
+// updates it if any setting in schema differs from the server.  It returns true
+// if a change was made.  When the client has a namespace only a collection
+// owned by that app counts, not one shared by another app.
+func (c *Client) EnsureKVStoreCollection(schema *KVStoreCollectionSchema) (bool, error) {
+	return c.EnsureKVStoreCollectionContext(context.Background(), schema)
+}
+
+// EnsureKVStoreCollectionContext is EnsureKVStoreCollection with a context that
+// cancels the requests.
+func (c *Client) EnsureKVStoreCollectionContext(ctx context.Context,
+	schema *KVStoreCollectionSchema) (bool, error) {
+	collections, err := c.ListKVStoreCollectionsContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, current := range collections {
+		if current.Name != schema.Name {
+			continue
+		}
+		if len(c.Namespace) > 0 && appFromEntityID(current.ID) != c.Namespace {
+			continue
+		}
+
+		if current.satisfies(schema) {
+			return false, nil
+		}
+		return true, c.UpdateKVStoreCollectionContext(ctx, schema)
+	}
+
+	return true, c.CreateKVStoreCollectionContext(ctx, schema)
+}
+
+// satisfies returns true if every setting in desired is already set on schema.
+func (schema *KVStoreCollectionSchema) satisfies(desired *KVStoreCollectionSchema) bool {
+	if schema.EnforceTypes != desired.EnforceTypes {
+		return false
+	}
+
+	for name, fieldType := range desired.Fields {
+		if schema.Fields[name] != fieldType {
+			return false
+		}
+	}
+
+	for name, definition := range desired.AcceleratedFields {
+		if strings.Join(strings.Fields(schema.AcceleratedFields[name]), "") !=
+			strings.Join(strings.Fields(definition), "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (schema *KVStoreCollectionSchema) params() url.Values {
+	params := url.Values{}
+	for name, fieldType := range schema.Fields {
+		params.Set("field."+name, string(fieldType))
+	}
+	for name, definition := range schema.AcceleratedFields {
+		params.Set("accelerated_fields."+name, definition)
+	}
+	params.Set("enforceTypes", strconv.FormatBool(schema.EnforceTypes))
+	return params
+}
+
+func newKVStoreCollectionSchemaFromEntry(entry RestEntry) *KVStoreCollectionSchema {
+	result := &KVStoreCollectionSchema{
+		Name:              entry.Title,
+		ID:                entry.ID,
+		Fields:            make(map[string]KVStoreFieldType),
+		AcceleratedFields: make(map[string]string),
+	}
+
+	for name, value := range entry.Contents.Map() {
+		switch {
+		case strings.HasPrefix(name, "field."):
+			result.Fields[strings.TrimPrefix(name, "field.")] = KVStoreFieldType(value)
+		case strings.HasPrefix(name, "accelerated_fields."):
+			result.AcceleratedFields[strings.TrimPrefix(name, "accelerated_fields.")] = value
+		case name == "enforceTypes":
+			result.EnforceTypes = parseSplunkBool(value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const kvStoreCollectionsResponse string = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">
+  <title>collections-conf</title>
+  <entry>
+    <title>fitbit_tokens</title>
+    <id>https://localhost:8089/servicesNS/nobody/fitness/storage/collections/config/fitbit_tokens</id>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="accelerated_fields.by_name">{"name": 1}</s:key>
+        <s:key name="enforceTypes">false</s:key>
+        <s:key name="field.name">string</s:key>
+        <s:key name="field.expiry">time</s:key>
+      </s:dict>
+    </content>
+  </entry>
+</feed>`
+
+func TestEnsureKVStoreCollection(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodGet {
+			w.Write([]byte(kvStoreCollectionsResponse))
+			return
+		}
+
+		if r.URL.Path == "/servicesNS/nobody/fitness/storage/collections/config/fitbit_tokens" &&
+			r.PostForm.Get("field.age") != "number" {
+			t.Errorf("Expected field.age to be set: %v", r.PostForm)
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+
+	changed, err := c.EnsureKVStoreCollection(&KVStoreCollectionSchema{
+		Name:              "fitbit_tokens",
+		Fields:            map[string]KVStoreFieldType{"name": KVStoreFieldString},
+		AcceleratedFields: map[string]string{"by_name": `{"name":1}`},
+	})
+	if err != nil {
+		t.Fatalf("Failed to ensure collection: %v", err)
+	}
+	if changed {
+		t.Logf("Expected no change to an up to date collection: %v", requests)
+		t.Fail()
+	}
+
+	changed, err = c.EnsureKVStoreCollection(&KVStoreCollectionSchema{
+		Name:   "fitbit_tokens",
+		Fields: map[string]KVStoreFieldType{"age": KVStoreFieldNumber},
+	})
+	if err != nil || !changed {
+		t.Fatalf("Expected collection to be updated: %v %v", changed, err)
+	}
+
+	requests = []string{}
+	_, err = c.EnsureKVStoreCollection(&KVStoreCollectionSchema{Name: "google_tokens"})
+	if err != nil {
+		t.Fatalf("Failed to create collection: %v", err)
+	}
+
+	expected := []string{
+		"GET /servicesNS/nobody/fitness/storage/collections/config",
+		"POST /servicesNS/nobody/fitness/storage/collections/config",
+		"POST /servicesNS/nobody/fitness/storage/collections/config/google_tokens",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Logf("Incorrect request. Expected: %v Received: %v", expected[i], requests[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestEnsureKVStoreCollectionOtherApp(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodGet {
+			w.Write([]byte(kvStoreCollectionsResponse))
+			return
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	// fitbit_tokens belongs to the fitness app, so it is created in google.
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "google"}
+	changed, err := c.EnsureKVStoreCollection(&KVStoreCollectionSchema{
+		Name:   "fitbit_tokens",
+		Fields: map[string]KVStoreFieldType{"name": KVStoreFieldString},
+	})
+	if err != nil || !changed {
+		t.Fatalf("Expected collection to be created: %v %v", changed, err)
+	}
+
+	expected := []string{
+		"GET /servicesNS/nobody/google/storage/collections/config",
+		"POST /servicesNS/nobody/google/storage/collections/config",
+		"POST /servicesNS/nobody/google/storage/collections/config/fitbit_tokens",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+		t.Fail()
+	}
+}
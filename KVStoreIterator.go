@@ -0,0 +1,148 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// DefaultKVStorePageSize is the number of records requested per page when
+// iterating over a collection.
+const DefaultKVStorePageSize = 1000
+
+// KVStoreIterator pages through the records of a KV Store collection.  Call
+// Next to advance to each record, Decode to read it and Err once Next returns
+// false.
+//
+//	it := c.KVStoreIterate(ctx, "fitbit_tokens", nil, 0)
+//	for it.Next() {
+//		user := &User{}
+//		if err := it.Decode(user); err != nil {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type KVStoreIterator struct {
+	client     *Client
+	ctx        context.Context
+	collection string
+	query      KVStoreQuery
+	pageSize   int
+
+	page    []json.RawMessage
+	index   int
+	fetched int
+	last    bool
+	current json.RawMessage
+	err     error
+}
+
+// KVStoreIterate returns an iterator over the records in a collection that
+// match query, requesting pageSize records at a time.  The results are sorted
+// by _key, after any sort in query, so that records are not skipped or
+// repeated between pages.  query may be nil and pageSize may be 0 to use
+// DefaultKVStorePageSize.  Skip and Limit in query apply to the whole
+// iteration.
+func (c *Client) KVStoreIterate(ctx context.Context, collection string,
+	query *KVStoreQuery, pageSize int) *KVStoreIterator {
+
+	if pageSize <= 0 {
+		pageSize = DefaultKVStorePageSize
+	}
+
+	it := &KVStoreIterator{
+		client:     c,
+		ctx:        ctx,
+		collection: collection,
+		pageSize:   pageSize,
+	}
+
+	if query != nil {
+		it.query = *query
+	}
+
+	sortedByKey := false
+	for _, sort := range it.query.Sort {
+		if sort.Field == "_key" {
+			sortedByKey = true
+		}
+	}
+	if !sortedByKey {
+		it.query.Sort = append(append([]KVStoreSort{}, it.query.Sort...),
+			KVStoreSort{Field: "_key"})
+	}
+
+	return it
+}
+
+// Next advances to the next record, requesting another page when needed.  It
+// returns false when there are no more records, the context is cancelled or a
+// request fails.
+func (it *KVStoreIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if it.index >= len(it.page) {
+		if it.last || !it.fetch() {
+			it.current = nil
+			return false
+		}
+	}
+
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Decode decodes the current record into the value pointed to by into.
+func (it *KVStoreIterator) Decode(into interface{}) error {
+	if it.current == nil {
+		return errors.New("No current record, call Next first.")
+	}
+	return json.Unmarshal(it.current, into)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *KVStoreIterator) Err() error {
+	return it.err
+}
+
+// fetch requests the next page of records and returns true if it has any.
+func (it *KVStoreIterator) fetch() bool {
+	limit := it.pageSize
+	if it.query.Limit > 0 {
+		remaining := it.query.Limit - it.fetched
+		if remaining <= 0 {
+			it.last = true
+			return false
+		}
+		if remaining < limit {
+			limit = remaining
+		}
+	}
+
+	query := it.query
+	query.Skip = it.query.Skip + it.fetched
+	query.Limit = limit
+
+	page := []json.RawMessage{}
+	it.err = it.client.KVStoreFindContext(it.ctx, it.collection, &query, &page)
+	if it.err != nil {
+		return false
+	}
+
+	it.page = page
+	it.index = 0
+	it.fetched += len(page)
+	it.last = len(page) < limit
+
+	return len(page) > 0
+}
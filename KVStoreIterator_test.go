@@ -0,0 +1,107 @@
+package splunk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func newKVStorePagingServer(t *testing.T, total int, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+		if r.URL.Query().Get("sort") != "name:-1,_key:1" {
+			t.Errorf("Expected _key to be added to the sort: %v", r.URL.RawQuery)
+		}
+
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		result := "["
+		for i := skip; i < skip+limit && i < total; i++ {
+			if i > skip {
+				result += ","
+			}
+			result += fmt.Sprintf(`{"_key":"%v"}`, i)
+		}
+		w.Write([]byte(result + "]"))
+	}))
+}
+
+func TestKVStoreIterate(t *testing.T) {
+	requests := []string{}
+	server := newKVStorePagingServer(t, 5, &requests)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	query := &KVStoreQuery{Sort: []KVStoreSort{{Field: "name", Descending: true}}}
+	it := c.KVStoreIterate(context.Background(), "tokens", query, 2)
+
+	keys := []string{}
+	for it.Next() {
+		record := struct {
+			Key string `json:"_key"`
+		}{}
+		if err := it.Decode(&record); err != nil {
+			t.Fatalf("Failed to decode record: %v", err)
+		}
+		keys = append(keys, record.Key)
+	}
+
+	if it.Err() != nil {
+		t.Fatalf("Iteration failed: %v", it.Err())
+	}
+
+	if fmt.Sprint(keys) != "[0 1 2 3 4]" {
+		t.Logf("Incorrect records returned: %v", keys)
+		t.Fail()
+	}
+
+	if len(requests) != 3 {
+		t.Logf("Expected 3 pages to be requested: %v", requests)
+		t.Fail()
+	}
+
+	if len(query.Sort) != 1 {
+		t.Logf("The caller's query should not be modified: %v", query.Sort)
+		t.Fail()
+	}
+}
+
+func TestKVStoreIterateLimitAndCancel(t *testing.T) {
+	requests := []string{}
+	server := newKVStorePagingServer(t, 10, &requests)
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	query := &KVStoreQuery{
+		Sort:  []KVStoreSort{{Field: "name", Descending: true}},
+		Skip:  1,
+		Limit: 3,
+	}
+
+	count := 0
+	it := c.KVStoreIterate(context.Background(), "tokens", query, 2)
+	for it.Next() {
+		count++
+	}
+	if count != 3 || it.Err() != nil {
+		t.Logf("Expected 3 records, received: %v %v", count, it.Err())
+		t.Fail()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it = c.KVStoreIterate(ctx, "tokens", query, 2)
+	it.Next()
+	cancel()
+
+	if it.Next() {
+		t.Log("Expected iteration to stop when the context is cancelled")
+		t.Fail()
+	}
+	if it.Err() != context.Canceled {
+		t.Logf("Expected context.Canceled, received: %v", it.Err())
+		t.Fail()
+	}
+}
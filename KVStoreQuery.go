@@ -0,0 +1,193 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// KVStoreFilter is a condition on the records returned by a KV Store query.  It
+// encodes to the JSON expected by the query parameter, build it with the KV
+// helper functions rather than by hand.
+type KVStoreFilter map[string]interface{}
+
+// KVEq matches records where field is equal to value.
+func KVEq(field string, value interface{}) KVStoreFilter {
+	return KVStoreFilter{field: value}
+}
+
+// KVNe matches records where field is not equal to value.
+func KVNe(field string, value interface{}) KVStoreFilter {
+	return kvOperator(field, "$ne", value)
+}
+
+// KVGt matches records where field is greater than value.
+func KVGt(field string, value interface{}) KVStoreFilter {
+	return kvOperator(field, "$gt", value)
+}
+
+// KVGte matches records where field is greater than or equal to value.
+func KVGte(field string, value interface{}) KVStoreFilter {
+	return kvOperator(field, "$gte", value)
+}
+
+// KVLt matches records where field is less than value.
+func KVLt(field string, value interface{}) KVStoreFilter {
+	return kvOperator(field, "$lt", value)
+}
+
+// KVLte matches records where field is less than or equal to value.
+func KVLte(field string, value interface{}) KVStoreFilter {
+	return kvOperator(field, "$lte", value)
+}
+
+// KVRegex matches records where field matches the regular expression pattern.
+func KVRegex(field, pattern string) KVStoreFilter {
+	return kvOperator(field, "$regex", pattern)
+}
+
+// KVAnd matches records that match all of the filters.
+func KVAnd(filters ...KVStoreFilter) KVStoreFilter {
+	return KVStoreFilter{"$and": filters}
+}
+
+// KVOr matches records that match any of the filters.
+func KVOr(filters ...KVStoreFilter) KVStoreFilter {
+	return KVStoreFilter{"$or": filters}
+}
+
+func kvOperator(field, operator string, value interface{}) KVStoreFilter {
+	return KVStoreFilter{field: map[string]interface{}{operator: value}}
+}
+
+// KVStoreSort is a field to sort the results of a KV Store query by.
+type KVStoreSort struct {
+	Field      string
+	Descending bool
+}
+
+// KVStoreQuery limits the records returned from a KV Store collection.  Zero
+// values are not sent, so an empty query returns the whole collection.
+type KVStoreQuery struct {
+	Filter KVStoreFilter
+
+	// Fields limits the fields returned to those listed.
+	Fields []string
+	// ExcludeFields removes the listed fields from the results.  Splunk only
+	// allows _key to be excluded when Fields is also set.
+	ExcludeFields []string
+
+	Sort  []KVStoreSort
+	Skip  int
+	Limit int
+}
+
+// params encodes the query as the parameters used by the KV Store REST API.
+func (query *KVStoreQuery) params() (url.Values, error) {
+	params := url.Values{}
+	if query == nil {
+		return params, nil
+	}
+
+	if len(query.Filter) > 0 {
+		b, err := json.Marshal(query.Filter)
+		if err != nil {
+			return params, err
+		}
+		params.Set("query", string(b))
+	}
+
+	fields := []string{}
+	for _, field := range query.Fields {
+		fields = append(fields, field+":1")
+	}
+	for _, field := range query.ExcludeFields {
+		fields = append(fields, field+":0")
+	}
+	if len(fields) > 0 {
+		params.Set("fields", strings.Join(fields, ","))
+	}
+
+	sorts := []string{}
+	for _, sort := range query.Sort {
+		if sort.Descending {
+			sorts = append(sorts, sort.Field+":-1")
+		} else {
+			sorts = append(sorts, sort.Field+":1")
+		}
+	}
+	if len(sorts) > 0 {
+		params.Set("sort", strings.Join(sorts, ","))
+	}
+
+	if query.Skip > 0 {
+		params.Set("skip", strconv.Itoa(query.Skip))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	return params, nil
+}
+
+// KVStoreQueryCollection returns the records in a KV Store collection that
+// match query.  Like KVStoreGetCollection the result is an io.ReadCloser that
+// can be JSON decoded.
+func (c *Client) KVStoreQueryCollection(collection string,
+	query *KVStoreQuery) (io.ReadCloser, error) {
+	return c.KVStoreQueryCollectionContext(context.Background(), collection, query)
+}
+
+// KVStoreQueryCollectionContext is KVStoreQueryCollection with a context that
+// cancels the request.
+func (c *Client) KVStoreQueryCollectionContext(ctx context.Context,
+	collection string, query *KVStoreQuery) (io.ReadCloser, error) {
+
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := c.buildRequestPath(
+		c.servicesPath("storage", "collections", "data", collection))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.makeRestRequestContext(ctx, http.MethodGet, u, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// KVStoreFind decodes the records in a KV Store collection that match query
+// into the slice pointed to by into.
+func (c *Client) KVStoreFind(collection string, query *KVStoreQuery,
+	into interface{}) error {
+	return c.KVStoreFindContext(context.Background(), collection, query, into)
+}
+
+// KVStoreFindContext is KVStoreFind with a context that cancels the request.
+func (c *Client) KVStoreFindContext(ctx context.Context, collection string,
+	query *KVStoreQuery, into interface{}) error {
+
+	params, err := query.params()
+	if err != nil {
+		return err
+	}
+
+	return c.kvStoreRequestContext(ctx, http.MethodGet,
+		[]string{"storage", "collections", "data", collection},
+		params, nil, into)
+}
@@ -0,0 +1,71 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKVStoreQueryParams(t *testing.T) {
+	query := &KVStoreQuery{
+		Filter: KVAnd(
+			KVEq("name", "bob"),
+			KVOr(KVGt("age", 30), KVRegex("email", "@example\\.com$")),
+		),
+		Fields:        []string{"name", "age"},
+		ExcludeFields: []string{"_key"},
+		Sort:          []KVStoreSort{{Field: "age", Descending: true}, {Field: "name"}},
+		Skip:          20,
+		Limit:         10,
+	}
+
+	params, err := query.params()
+	if err != nil {
+		t.Fatalf("Failed to encode query: %v", err)
+	}
+
+	expected := map[string]string{
+		"query":  `{"$and":[{"name":"bob"},{"$or":[{"age":{"$gt":30}},{"email":{"$regex":"@example\\.com$"}}]}]}`,
+		"fields": "name:1,age:1,_key:0",
+		"sort":   "age:-1,name:1",
+		"skip":   "20",
+		"limit":  "10",
+	}
+
+	for name, value := range expected {
+		if params.Get(name) != value {
+			t.Logf("Incorrect %v parameter. Expected: %v Received: %v",
+				name, value, params.Get(name))
+			t.Fail()
+		}
+	}
+}
+
+func TestKVStoreFind(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != `{"name":"bob"}` ||
+			r.URL.Query().Get("limit") != "1" {
+			t.Errorf("Incorrect query: %v", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"_key":"1","name":"bob"}]`))
+	}))
+	defer server.Close()
+
+	type User struct {
+		Key  string `json:"_key"`
+		Name string `json:"name"`
+	}
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	result := []User{}
+	err := c.KVStoreFind("tokens",
+		&KVStoreQuery{Filter: KVEq("name", "bob"), Limit: 1}, &result)
+	if err != nil {
+		t.Fatalf("Failed to query collection: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Key != "1" {
+		t.Logf("Incorrect records returned: %v", result)
+		t.Fail()
+	}
+}
@@ -0,0 +1,131 @@
+package splunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// KVStoreUpdateRetries is the number of times KVStoreUpdateFunc retries an
+// update after a conflict.
+const KVStoreUpdateRetries = 5
+
+// ErrKVStoreConflict is returned by KVStoreUpdateFunc when the record kept
+// changing while it was being updated.
+var ErrKVStoreConflict = errors.New("KV Store record was modified by another writer.")
+
+// KVStoreUpdateFunc updates a record using a version field for optimistic
+// concurrency.  The record with the given key is decoded into the value
+// pointed to by record and update is called to modify it.  The record is only
+// written if versionField is unchanged on the server, and the version is
+// incremented when it is written.  If another writer changed the record it is
+// read again and update is called again, so update must be safe to repeat.
+//
+// The KV Store has no conditional writes, so this narrows the window for lost
+// updates to the time between the version check and the write rather than
+// closing it completely.
+func (c *Client) KVStoreUpdateFunc(collection, key, versionField string,
+	record interface{}, update func() error) error {
+	return c.KVStoreUpdateFuncContext(context.Background(), collection, key,
+		versionField, record, update)
+}
+
+// KVStoreUpdateFuncContext is KVStoreUpdateFunc with a context that cancels the
+// requests.
+func (c *Client) KVStoreUpdateFuncContext(ctx context.Context, collection, key,
+	versionField string, record interface{}, update func() error) error {
+
+	target := reflect.ValueOf(record)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New("KVStoreUpdateFunc requires a non-nil pointer to a record.")
+	}
+
+	for attempt := 0; attempt <= KVStoreUpdateRetries; attempt++ {
+		raw := json.RawMessage{}
+		err := c.KVStoreGetContext(ctx, collection, key, &raw)
+		if err != nil {
+			return err
+		}
+
+		version, err := kvStoreVersion(raw, versionField)
+		if err != nil {
+			return err
+		}
+
+		target.Elem().Set(reflect.Zero(target.Elem().Type()))
+		if err = json.Unmarshal(raw, record); err != nil {
+			return err
+		}
+
+		if err = update(); err != nil {
+			return err
+		}
+
+		payload, err := kvStoreVersionedPayload(record, versionField, version+1)
+		if err != nil {
+			return err
+		}
+
+		current := json.RawMessage{}
+		if err = c.KVStoreGetContext(ctx, collection, key, &current); err != nil {
+			return err
+		}
+
+		currentVersion, err := kvStoreVersion(current, versionField)
+		if err != nil {
+			return err
+		}
+		if currentVersion != version {
+			continue
+		}
+
+		return c.KVStoreUpdateContext(ctx, collection, key, payload)
+	}
+
+	return ErrKVStoreConflict
+}
+
+// kvStoreVersion returns the value of versionField in a record, or 0 if the
+// record doesn't have one.
+func kvStoreVersion(raw json.RawMessage, versionField string) (int64, error) {
+	fields := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return 0, err
+	}
+
+	switch value := fields[versionField].(type) {
+	case nil:
+		return 0, nil
+	case json.Number:
+		return strconv.ParseInt(value.String(), 10, 64)
+	case string:
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	return 0, errors.New("Version field " + versionField + " is not a number.")
+}
+
+// kvStoreVersionedPayload encodes record with versionField set to version.
+func kvStoreVersionedPayload(record interface{}, versionField string,
+	version int64) (map[string]interface{}, error) {
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err = decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	payload[versionField] = version
+	return payload, nil
+}
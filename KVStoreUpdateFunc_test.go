@@ -0,0 +1,60 @@
+package splunk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKVStoreUpdateFunc(t *testing.T) {
+	stored := `{"_key":"1","token":"a","version":1}`
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			// Another writer updates the record between the first read and
+			// the version check.
+			if gets == 2 {
+				stored = `{"_key":"1","token":"b","version":2}`
+			}
+			w.Write([]byte(stored))
+		case http.MethodPost:
+			payload := make(map[string]interface{})
+			json.NewDecoder(r.Body).Decode(&payload)
+			b, _ := json.Marshal(payload)
+			stored = string(b)
+			w.Write([]byte(`{"_key":"1"}`))
+		}
+	}))
+	defer server.Close()
+
+	type Token struct {
+		Key   string `json:"_key"`
+		Token string `json:"token"`
+	}
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	record := &Token{}
+	calls := 0
+	err := c.KVStoreUpdateFunc("tokens", "1", "version", record, func() error {
+		calls++
+		record.Token += "+refreshed"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to update record: %v", err)
+	}
+
+	if calls != 2 {
+		t.Logf("Expected update to be retried after a conflict, calls: %v", calls)
+		t.Fail()
+	}
+
+	expected := `{"_key":"1","token":"b+refreshed","version":3}`
+	if stored != expected {
+		t.Logf("Incorrect record stored.\nExpected: %v\nReceived: %v", expected, stored)
+		t.Fail()
+	}
+}
@@ -0,0 +1,167 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKVStoreInsertAndGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost &&
+			r.URL.Path == "/servicesNS/nobody/fitness/storage/collections/data/tokens":
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Incorrect content type: %v", r.Header.Get("Content-Type"))
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			if string(b) != `{"name":"bob"}` {
+				t.Errorf("Incorrect payload: %s", b)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"_key":"5410be5441ba15298e4624d1"}`))
+		case r.Method == http.MethodGet &&
+			r.URL.Path == "/servicesNS/nobody/fitness/storage/collections/data/tokens/5410be5441ba15298e4624d1":
+			w.Write([]byte(`{"_key":"5410be5441ba15298e4624d1","name":"bob"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	type User struct {
+		Key  string `json:"_key,omitempty"`
+		Name string `json:"name"`
+	}
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	key, err := c.KVStoreInsert("tokens", &User{Name: "bob"})
+	if err != nil {
+		t.Fatalf("Failed to insert record: %v", err)
+	}
+
+	if key != "5410be5441ba15298e4624d1" {
+		t.Logf("Incorrect key returned: %v", key)
+		t.Fail()
+	}
+
+	user := &User{}
+	err = c.KVStoreGet("tokens", key, user)
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+
+	if user.Name != "bob" || user.Key != key {
+		t.Logf("Incorrect record returned: %v", user)
+		t.Fail()
+	}
+
+	err = c.KVStoreDelete("tokens", "missing")
+	if err == nil {
+		t.Log("Expected an error deleting a missing record")
+		t.Fail()
+	}
+}
+
+func TestKVStoreDeleteQueryAndBatchSave(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			query := map[string]string{}
+			json.Unmarshal([]byte(r.URL.Query().Get("query")), &query)
+			if query["name"] != "bob" {
+				t.Errorf("Incorrect delete query: %v", r.URL.RawQuery)
+			}
+		case http.MethodPost:
+			if r.URL.Path != "/servicesNS/nobody/fitness/storage/collections/data/tokens/batch_save" {
+				t.Errorf("Incorrect path requested: %v", r.URL.Path)
+			}
+			w.Write([]byte(`["a","b"]`))
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	err := c.KVStoreDeleteQuery("tokens", map[string]string{"name": "bob"})
+	if err != nil {
+		t.Fatalf("Failed to delete by query: %v", err)
+	}
+
+	keys, err := c.KVStoreBatchSave("tokens", []map[string]string{
+		{"name": "alice"}, {"name": "bob"}})
+	if err != nil {
+		t.Fatalf("Failed to batch save: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "a" {
+		t.Logf("Incorrect keys returned: %v", keys)
+		t.Fail()
+	}
+}
+
+func TestKVStoreGetContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	record := map[string]string{}
+	err := c.KVStoreGetContext(ctx, "tokens", "abc", &record)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Logf("Expected context.DeadlineExceeded, received: %v", err)
+		t.Fail()
+	}
+}
+
+func TestKVStoreKeyEscaping(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	key := "https://example.com/users/bob"
+	record := map[string]string{}
+	if err := c.KVStoreGet("tokens", key, &record); err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if err := c.KVStoreUpdate("tokens", key, record); err != nil {
+		t.Fatalf("Failed to update record: %v", err)
+	}
+	if err := c.KVStoreDelete("tokens", key); err != nil {
+		t.Fatalf("Failed to delete record: %v", err)
+	}
+
+	reader, err := c.KVStoreGetCollection("tokens")
+	if err != nil {
+		t.Fatalf("Failed to get collection: %v", err)
+	}
+	reader.Close()
+
+	path := "/services/storage/collections/data/tokens"
+	recordPath := path + "/https:%2F%2Fexample.com%2Fusers%2Fbob"
+	expected := []string{
+		"GET " + recordPath,
+		"POST " + recordPath,
+		"DELETE " + recordPath,
+		"GET " + path,
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,95 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SecretMask is written to inputs.conf in place of a secret once it has been
+// moved to storage/passwords.
+const SecretMask = "********"
+
+// MaskStanzaSecret keeps the secret in a stanza parameter out of inputs.conf.
+// The first time it sees a plain text value it stores it in storage/passwords,
+// with the stanza name as the realm and the parameter name as the username,
+// and replaces the value in inputs.conf and in stanza with SecretMask.  On
+// later runs it reads the secret back from storage/passwords.  The clear text
+// secret is returned.  The client must have the namespace of the app that
+// owns the input.
+func (c *Client) MaskStanzaSecret(stanza *ModInputStanza, param string) (string, error) {
+	return c.MaskStanzaSecretContext(context.Background(), stanza, param)
+}
+
+// MaskStanzaSecretContext is MaskStanzaSecret with a context that cancels the
+// requests.
+func (c *Client) MaskStanzaSecretContext(ctx context.Context,
+	stanza *ModInputStanza, param string) (string, error) {
+
+	value := stanza.ParamMap[param]
+	if len(value) == 0 {
+		return "", nil
+	}
+
+	if value == SecretMask {
+		credential, err := c.GetCredentialContext(ctx, stanza.StanzaName, param)
+		if err != nil {
+			return "", err
+		}
+		return credential.ClearPassword, nil
+	}
+
+	scheme, name, err := splitStanzaName(stanza.StanzaName)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.GetCredentialContext(ctx, stanza.StanzaName, param)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		_, err = c.UpdateCredentialContext(ctx, stanza.StanzaName, param, value)
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		_, err = c.CreateCredentialContext(ctx, stanza.StanzaName, param, value)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set(param, SecretMask)
+	_, err = c.namedEntityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("data", "inputs", scheme), name, params)
+	if err != nil {
+		return "", err
+	}
+
+	stanza.setParameter(param, SecretMask)
+	return value, nil
+}
+
+// setParameter replaces the value of a parameter in the stanza.
+func (stanza *ModInputStanza) setParameter(name, value string) {
+	for i, param := range stanza.Params {
+		if param.Name == name {
+			stanza.Params[i].Value = value
+		}
+	}
+
+	if stanza.ParamMap == nil {
+		stanza.ParamMap = make(map[string]string)
+	}
+	stanza.ParamMap[name] = value
+}
+
+// splitStanzaName splits a stanza name such as myScheme://aaa into the scheme
+// and the name of the input.
+func splitStanzaName(stanzaName string) (string, string, error) {
+	pieces := strings.SplitN(stanzaName, "://", 2)
+	if len(pieces) != 2 || len(pieces[0]) == 0 || len(pieces[1]) == 0 {
+		return "", "", errors.New("Invalid stanza name: " + stanzaName)
+	}
+	return pieces[0], pieces[1], nil
+}
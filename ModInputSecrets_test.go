@@ -0,0 +1,103 @@
+package splunk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaskStanzaSecret(t *testing.T) {
+	stored := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		const prefix = "/servicesNS/nobody/TA-S3/"
+
+		switch {
+		case r.URL.Path == prefix+"storage/passwords" && r.Method == http.MethodPost:
+			name := r.PostForm.Get("realm") + ":" + r.PostForm.Get("name") + ":"
+			stored[name] = r.PostForm.Get("password")
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>` + name + `</title></entry></feed>`))
+
+		case strings.HasPrefix(r.URL.Path, prefix+"storage/passwords/"):
+			name := strings.TrimPrefix(r.URL.Path, prefix+"storage/passwords/")
+			password, ok := stored[strings.Replace(name, `\:`, ":", -1)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">` +
+				`<entry><title>` + name + `</title><content><s:dict>` +
+				`<s:key name="clear_password">` + password + `</s:key>` +
+				`</s:dict></content></entry></feed>`))
+
+		case r.URL.EscapedPath() == prefix+"data/inputs/s3/bucket%2Fdir" && r.Method == http.MethodPost:
+			if r.PostForm.Get("secret_key") != SecretMask {
+				t.Errorf("Expected secret_key to be masked: %v", r.PostForm)
+			}
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+
+		default:
+			t.Errorf("Unexpected request: %v %v", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stanza := &ModInputStanza{StanzaName: "s3://bucket/dir"}
+	stanza.AddParameter("secret_key", "hunter2")
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "TA-S3"}
+	secret, err := c.MaskStanzaSecret(stanza, "secret_key")
+	if err != nil {
+		t.Fatalf("Failed to mask secret: %v", err)
+	}
+
+	if secret != "hunter2" || stored["s3://bucket/dir:secret_key:"] != "hunter2" {
+		t.Logf("Secret was not stored: %v %v", secret, stored)
+		t.Fail()
+	}
+
+	if stanza.ParamMap["secret_key"] != SecretMask || stanza.Params[0].Value != SecretMask {
+		t.Logf("Expected the stanza parameter to be masked: %v", stanza.Params)
+		t.Fail()
+	}
+
+	secret, err = c.MaskStanzaSecret(stanza, "secret_key")
+	if err != nil {
+		t.Fatalf("Failed to read masked secret: %v", err)
+	}
+	if secret != "hunter2" {
+		t.Logf("Incorrect secret read back. Expected: hunter2 Received: %v", secret)
+		t.Fail()
+	}
+}
+
+func TestMaskStanzaSecretLookupError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Credential should not be written after a failed lookup: %v %v",
+				r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	stanza := &ModInputStanza{StanzaName: "s3://bucket"}
+	stanza.AddParameter("secret_key", "hunter2")
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "TA-S3"}
+	_, err := c.MaskStanzaSecret(stanza, "secret_key")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Logf("Expected the lookup error to be returned, received: %v", err)
+		t.Fail()
+	}
+
+	if stanza.ParamMap["secret_key"] != "hunter2" {
+		t.Logf("Stanza should not be masked after an error: %v", stanza.ParamMap)
+		t.Fail()
+	}
+}
@@ -0,0 +1,146 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StoredCredential is a credential kept in Splunk's storage/passwords endpoint.
+type StoredCredential struct {
+	// Name identifies the credential and is in the form realm:username:
+	Name          string
+	Realm         string
+	Username      string
+	ClearPassword string
+}
+
+// ListCredentials returns all of the stored credentials visible to the client.
+func (c *Client) ListCredentials() ([]*StoredCredential, error) {
+	return c.ListCredentialsContext(context.Background())
+}
+
+// ListCredentialsContext is ListCredentials with a context that cancels the
+// request.
+func (c *Client) ListCredentialsContext(ctx context.Context) ([]*StoredCredential, error) {
+	params := url.Values{}
+	params.Set("count", "0")
+
+	resp, err := c.entityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("storage", "passwords"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*StoredCredential{}
+	for _, entry := range resp.Entries {
+		result = append(result, newStoredCredentialFromEntry(entry))
+	}
+	return result, nil
+}
+
+// GetCredential returns the stored credential for a realm and username.  The
+// realm may be empty.
+func (c *Client) GetCredential(realm, username string) (*StoredCredential, error) {
+	return c.GetCredentialContext(context.Background(), realm, username)
+}
+
+// GetCredentialContext is GetCredential with a context that cancels the
+// request.
+func (c *Client) GetCredentialContext(ctx context.Context, realm,
+	username string) (*StoredCredential, error) {
+
+	resp, err := c.namedEntityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("storage", "passwords"), credentialName(realm, username), nil)
+	if err != nil {
+		return nil, err
+	}
+	return firstStoredCredential(resp)
+}
+
+// CreateCredential stores a new credential.
+func (c *Client) CreateCredential(realm, username, password string) (*StoredCredential, error) {
+	return c.CreateCredentialContext(context.Background(), realm, username, password)
+}
+
+// CreateCredentialContext is CreateCredential with a context that cancels the
+// request.
+func (c *Client) CreateCredentialContext(ctx context.Context, realm, username,
+	password string) (*StoredCredential, error) {
+
+	params := url.Values{}
+	params.Set("name", username)
+	params.Set("password", password)
+	if len(realm) > 0 {
+		params.Set("realm", realm)
+	}
+
+	resp, err := c.entityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("storage", "passwords"), params)
+	if err != nil {
+		return nil, err
+	}
+	return firstStoredCredential(resp)
+}
+
+// UpdateCredential changes the password of an existing credential.
+func (c *Client) UpdateCredential(realm, username, password string) (*StoredCredential, error) {
+	return c.UpdateCredentialContext(context.Background(), realm, username, password)
+}
+
+// UpdateCredentialContext is UpdateCredential with a context that cancels the
+// request.
+func (c *Client) UpdateCredentialContext(ctx context.Context, realm, username,
+	password string) (*StoredCredential, error) {
+
+	params := url.Values{}
+	params.Set("password", password)
+
+	resp, err := c.namedEntityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("storage", "passwords"), credentialName(realm, username), params)
+	if err != nil {
+		return nil, err
+	}
+	return firstStoredCredential(resp)
+}
+
+// DeleteCredential deletes the stored credential for a realm and username.
+func (c *Client) DeleteCredential(realm, username string) error {
+	return c.DeleteCredentialContext(context.Background(), realm, username)
+}
+
+// DeleteCredentialContext is DeleteCredential with a context that cancels the
+// request.
+func (c *Client) DeleteCredentialContext(ctx context.Context, realm,
+	username string) error {
+
+	_, err := c.namedEntityRequestContext(ctx, http.MethodDelete,
+		c.servicesPath("storage", "passwords"), credentialName(realm, username), nil)
+	return err
+}
+
+// credentialName builds the name Splunk uses for a credential, realm:username:
+// with any colons in the realm or username escaped.
+func credentialName(realm, username string) string {
+	escape := strings.NewReplacer(":", `\:`)
+	return escape.Replace(realm) + ":" + escape.Replace(username) + ":"
+}
+
+func firstStoredCredential(resp *RestResponse) (*StoredCredential, error) {
+	if len(resp.Entries) == 0 {
+		return nil, errors.New("No credential returned.")
+	}
+	return newStoredCredentialFromEntry(resp.Entries[0]), nil
+}
+
+func newStoredCredentialFromEntry(entry RestEntry) *StoredCredential {
+	content := entry.Contents.Map()
+	return &StoredCredential{
+		Name:          entry.Title,
+		Realm:         content["realm"],
+		Username:      content["username"],
+		ClearPassword: content["clear_password"],
+	}
+}
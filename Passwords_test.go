@@ -0,0 +1,114 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCredentialName(t *testing.T) {
+	result := credentialName("", "bob")
+	if result != ":bob:" {
+		t.Logf("Incorrect credential name. Expected: :bob: Received: %v", result)
+		t.Fail()
+	}
+
+	result = credentialName("https://example.com", "bob")
+	if result != `https\://example.com:bob:` {
+		t.Logf(`Incorrect credential name. Expected: https\://example.com:bob: Received: %v`,
+			result)
+		t.Fail()
+	}
+}
+
+func TestGetCredential(t *testing.T) {
+	const username = "616872666934-ctkc2btlhme0or0vmar8mlaidt2g1j16.apps.googleusercontent.com"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/:"+username+":" {
+			t.Errorf("Incorrect path requested: %v", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Splunk 123102983109283019283" {
+			t.Errorf("Expected the session key from the mod input config: %v",
+				r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(passwordRestResponse))
+	}))
+	defer server.Close()
+
+	config, err := ReadModInputConfig(strings.NewReader(modInputConfigExample))
+	if err != nil {
+		t.Fatalf("Unable to read ModInputConfig: %v", err)
+	}
+	config.ServerURI = server.URL
+
+	c := NewClientFromModInputConfig(config, "TA-GoogleFitness", false)
+	credential, err := c.GetCredential("", username)
+	if err != nil {
+		t.Fatalf("Failed to get credential: %v", err)
+	}
+
+	if credential.ClearPassword != "clear_password_value" ||
+		credential.Username != username {
+		t.Logf("Incorrect credential returned: %v", credential)
+		t.Fail()
+	}
+}
+
+func TestListCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet ||
+			r.URL.Path != "/servicesNS/nobody/TA-GoogleFitness/storage/passwords" ||
+			r.URL.Query().Get("count") != "0" {
+			t.Errorf("Incorrect request: %v %v?%v", r.Method, r.URL.Path, r.URL.RawQuery)
+		}
+		w.Write([]byte(passwordRestResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "TA-GoogleFitness"}
+	credentials, err := c.ListCredentials()
+	if err != nil {
+		t.Fatalf("Failed to list credentials: %v", err)
+	}
+
+	if len(credentials) != 1 ||
+		credentials[0].ClearPassword != "clear_password_value" {
+		t.Logf("Incorrect credentials returned: %v", credentials)
+		t.Fail()
+	}
+}
+
+func TestCreateUpdateAndDeleteCredential(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+r.PostForm.Encode())
+		w.Write([]byte(passwordRestResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	if _, err := c.CreateCredential("https://example.com", "bob", "secret"); err != nil {
+		t.Fatalf("Failed to create credential: %v", err)
+	}
+	if _, err := c.UpdateCredential("https://example.com", "bob", "changed"); err != nil {
+		t.Fatalf("Failed to update credential: %v", err)
+	}
+	if err := c.DeleteCredential("https://example.com", "bob"); err != nil {
+		t.Fatalf("Failed to delete credential: %v", err)
+	}
+
+	path := "/servicesNS/nobody/search/storage/passwords"
+	expected := []string{
+		"POST " + path + " name=bob&password=secret&realm=https%3A%2F%2Fexample.com",
+		"POST " + path + "/https%5C:%2F%2Fexample.com:bob: password=changed",
+		"DELETE " + path + "/https%5C:%2F%2Fexample.com:bob: ",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+		t.Fail()
+	}
+}
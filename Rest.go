@@ -2,8 +2,7 @@ package splunk
 
 import (
 	"bytes"
-	"crypto/tls"
-	"encoding/json"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -11,17 +10,44 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 const LocalSplunkMgmntURL = "https://localhost:8089"
 const login_endpoint = "services/auth/login"
 
+type OutputMode string
+
+const OutputModeXML = "xml"
+const OutputModeJSON = "json"
+
 type Client struct {
 	SessionKey  string
 	Namespace   string
 	Owner       string
 	BaseURL     string
 	ValidateTLS bool
+
+	// OutputMode selects the format Splunk responds with.  Both are decoded
+	// into RestResponse, XML is used if it is empty.
+	OutputMode OutputMode
+
+	// Authenticator adds credentials to each request.  If it is nil the
+	// SessionKey is used.
+	Authenticator Authenticator
+
+	// HTTPClient sends every request.  If it is nil one is created from
+	// HTTPOptions and ValidateTLS on first use and reused after that.
+	HTTPClient  *http.Client
+	HTTPOptions HTTPOptions
+
+	httpMu sync.Mutex
+
+	// username and password are kept by Login so that an expired session key
+	// can be renewed.  authMu guards them and SessionKey.
+	username string
+	password string
+	authMu   sync.RWMutex
 }
 
 // NewClientFromSessionKey creates a Client object when the user already has
@@ -38,33 +64,72 @@ func NewClientFromSessionKey(sessionKey, namespace, owner, baseURL string,
 	}
 }
 
+// NewClientFromToken creates a Client that authenticates with a Splunk
+// authentication token instead of a session key.
+func NewClientFromToken(token, namespace, owner, baseURL string,
+	validateTLS bool) *Client {
+	return NewClientFromAuthenticator(TokenAuth(token), namespace, owner,
+		baseURL, validateTLS)
+}
+
+// NewClientFromAuthenticator creates a Client that uses auth to authenticate
+// its requests, e.g. BasicAuth or a custom Authenticator.
+func NewClientFromAuthenticator(auth Authenticator, namespace, owner,
+	baseURL string, validateTLS bool) *Client {
+	return &Client{
+		Authenticator: auth,
+		Namespace:     namespace,
+		Owner:         owner,
+		BaseURL:       baseURL,
+		ValidateTLS:   validateTLS,
+	}
+}
+
+// NewClientFromModInputConfig creates a Client using the server URI and session
+// key that Splunk passes to a modular input.
+func NewClientFromModInputConfig(config *ModInputConfig, namespace string,
+	validateTLS bool) *Client {
+	return NewClientFromSessionKey(config.SessionKey, namespace, "nobody",
+		config.ServerURI, validateTLS)
+}
+
 // NewClientFromLogin creates a Client object is used when the user must provide
-// their credentials in order to log in.
+// their credentials in order to log in.  The credentials are kept so that the
+// session key is renewed when it expires.
 func NewClientFromLogin(username, password, namespace, owner, baseURL string,
 	validateTLS bool) (*Client, error) {
+	return NewClientFromLoginContext(context.Background(), username, password,
+		namespace, owner, baseURL, validateTLS)
+}
 
-	c := Client{
+// NewClientFromLoginContext is NewClientFromLogin with a context that cancels
+// the login request.
+func NewClientFromLoginContext(ctx context.Context, username, password,
+	namespace, owner, baseURL string, validateTLS bool) (*Client, error) {
+
+	c := &Client{
 		Namespace:   namespace,
 		Owner:       owner,
 		BaseURL:     baseURL,
 		ValidateTLS: validateTLS,
 	}
 
-	key, err := c.getSessionKey(username, password)
+	err := c.LoginContext(ctx, username, password)
 	if err != nil {
 		return &Client{}, err
 	}
 
-	return &Client{
-		SessionKey:  key.SessionKey,
-		Namespace:   namespace,
-		Owner:       owner,
-		BaseURL:     baseURL,
-		ValidateTLS: validateTLS,
-	}, nil
+	return c, nil
 }
 
 func (c *Client) getSessionKey(username, password string) (*SessionKey, error) {
+	return c.getSessionKeyContext(context.Background(), username, password)
+}
+
+// getSessionKeyContext logs in with username and password and returns the
+// session key.  The context cancels the login request.
+func (c *Client) getSessionKeyContext(ctx context.Context, username,
+	password string) (*SessionKey, error) {
 
 	u, err := url.ParseRequestURI(c.BaseURL)
 	if err != nil {
@@ -79,14 +144,13 @@ func (c *Client) getSessionKey(username, password string) (*SessionKey, error) {
 	data.Set("username", username)
 	data.Add("password", password)
 
-	r, err := http.NewRequest(http.MethodPost, urlStr, bytes.NewBufferString(data.Encode()))
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return &SessionKey{}, err
 	}
 	r.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
 
-	client := c.newSplunkHttpClient()
-	resp, err := client.Do(r)
+	resp, err := c.httpClient().Do(r)
 
 	if err != nil {
 		return &SessionKey{}, err
@@ -94,6 +158,10 @@ func (c *Client) getSessionKey(username, password string) (*SessionKey, error) {
 
 	defer resp.Body.Close()
 
+	if err = checkResponse(resp); err != nil {
+		return &SessionKey{}, err
+	}
+
 	restResp := &SessionKey{}
 	decoder := xml.NewDecoder(resp.Body)
 	err = decoder.Decode(restResp)
@@ -104,105 +172,219 @@ func (c *Client) getSessionKey(username, password string) (*SessionKey, error) {
 	return restResp, nil
 }
 
+// GetEntities returns the entries at path.  Splunk only returns the first 30
+// entries by default, use GetEntitiesWithOptions or IterateEntities to get
+// more.
 func (c *Client) GetEntities(path []string) (*RestResponse, error) {
+	return c.GetEntitiesContext(context.Background(), path)
+}
+
+// GetEntitiesContext is GetEntities with a context that cancels the request.
+func (c *Client) GetEntitiesContext(ctx context.Context, path []string) (*RestResponse, error) {
+	return c.GetEntitiesWithOptionsContext(ctx, path, nil)
+}
+
+// entityRequestContext sends a request to an entity endpoint and decodes the
+// Atom feed that Splunk returns.  The context cancels the request.
+func (c *Client) entityRequestContext(ctx context.Context, method string,
+	path []string, params url.Values) (*RestResponse, error) {
 
 	u, err := c.buildRequestPath(path)
 	if err != nil {
 		return &RestResponse{}, err
 	}
 
-	resp, err := c.makeGetRestRequest(u)
+	return c.entityURLRequestContext(ctx, method, u, params)
+}
+
+// namedEntityRequestContext is entityRequestContext for the entity called name
+// in the collection at path, with the name escaped by buildEntityPath.
+func (c *Client) namedEntityRequestContext(ctx context.Context, method string,
+	path []string, name string, params url.Values) (*RestResponse, error) {
+
+	u, err := c.buildEntityPath(path, name)
+	if err != nil {
+		return &RestResponse{}, err
+	}
+
+	return c.entityURLRequestContext(ctx, method, u, params)
+}
+
+// entityURLRequestContext sends a request to the entity endpoint at u and
+// decodes the response.
+func (c *Client) entityURLRequestContext(ctx context.Context, method string,
+	u *url.URL, params url.Values) (*RestResponse, error) {
+
+	resp, err := c.makeRestRequestContext(ctx, method, u, c.outputModeParams(params))
 	if err != nil {
 		return &RestResponse{}, err
 	}
 
 	defer resp.Body.Close()
 
+	if err = checkResponse(resp); err != nil {
+		return &RestResponse{}, err
+	}
+
+	if c.OutputMode == OutputModeJSON {
+		return decodeJSONRestResponse(resp.Body)
+	}
+
 	//Decode the response from XML
 	decoder := xml.NewDecoder(resp.Body)
 	result := &RestResponse{}
 
 	err = decoder.Decode(result)
-	if err != nil {
+	if err != nil && err != io.EOF {
 		return &RestResponse{}, err
 	}
 
 	return result, nil
 }
 
+// outputModeParams returns a copy of params with output_mode set when the
+// client uses JSON.
+func (c *Client) outputModeParams(params url.Values) url.Values {
+	if c.OutputMode != OutputModeJSON {
+		return params
+	}
+
+	result := url.Values{}
+	for name, values := range params {
+		result[name] = values
+	}
+	result.Set("output_mode", OutputModeJSON)
+	return result
+}
+
 // KVStoreGetCollection returns values from a KV Store collection.  Result is a
 // io.ReadCloser so that it can be JSON decoder.
 func (c *Client) KVStoreGetCollection(collection string) (io.ReadCloser, error) {
+	return c.KVStoreGetCollectionContext(context.Background(), collection)
+}
+
+// KVStoreGetCollectionContext is KVStoreGetCollection with a context that
+// cancels the request.
+func (c *Client) KVStoreGetCollectionContext(ctx context.Context,
+	collection string) (io.ReadCloser, error) {
 
-	u, err := c.buildRequestPath([]string{"storage", "collections", "data", collection})
+	u, err := c.buildRequestPath(
+		c.servicesPath("storage", "collections", "data", collection))
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.makeGetRestRequest(u)
+	resp, err := c.makeGetRestRequest(ctx, u)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("Status: " + resp.Status + ":" + u.RequestURI())
-	}
-
 	return resp.Body, nil
 }
 
+// KVStoreUpdate replaces the record with the key id in a KV Store collection
+// with payload encoded as JSON.
 func (c *Client) KVStoreUpdate(collection, id string, payload interface{}) error {
-	u, err := c.buildRequestPath([]string{"storage", "collections", "data", collection, id})
+	return c.KVStoreUpdateContext(context.Background(), collection, id, payload)
+}
+
+// KVStoreUpdateContext is KVStoreUpdate with a context that cancels the
+// request.
+func (c *Client) KVStoreUpdateContext(ctx context.Context, collection, id string,
+	payload interface{}) error {
+	return c.kvStoreRecordRequestContext(ctx, http.MethodPost, collection, id,
+		payload, nil)
+}
 
+func (c *Client) makeGetRestRequest(ctx context.Context, u *url.URL) (*http.Response, error) {
+	resp, err := c.sendRequestContext(ctx, http.MethodGet, u, nil, "")
 	if err != nil {
-		return err
+		return &http.Response{}, err
 	}
 
-	//Encode the payload into JSON
-	b, err := json.Marshal(payload)
+	if err = checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return &http.Response{}, err
+	}
 
-	if err != nil {
-		return err
+	return resp, nil
+}
+
+// makeRestRequestContext sends a request with params to the REST API.  For GET
+// and DELETE requests the params are added to the query string, otherwise they
+// are form encoded in the body.  The status code of the response is not
+// checked.  The context cancels the request.
+func (c *Client) makeRestRequestContext(ctx context.Context, method string,
+	u *url.URL, params url.Values) (*http.Response, error) {
+
+	var body []byte
+	contentType := ""
+
+	switch method {
+	case http.MethodGet, http.MethodDelete:
+		if len(params) > 0 {
+			u.RawQuery = params.Encode()
+		}
+	default:
+		body = []byte(params.Encode())
+		contentType = "application/x-www-form-urlencoded"
 	}
-	reader := bytes.NewReader(b)
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%v", u), reader)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", "Splunk "+c.SessionKey)
+	return c.sendRequestContext(ctx, method, u, body, contentType)
+}
 
-	if err != nil {
-		return err
+// sendRequestContext creates a request authenticated with the Authenticator or
+// session key and sends it to Splunk.  The context cancels the request.  If
+// Splunk rejects the session key and the client logged in with Login, it logs
+// in again and retries the request once.
+func (c *Client) sendRequestContext(ctx context.Context, method string,
+	u *url.URL, body []byte, contentType string) (*http.Response, error) {
+
+	sessionKey := c.currentSessionKey()
+	resp, err := c.sendRequestWithKey(ctx, method, u, body, contentType, sessionKey)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.canRenewSessionKey() {
+		return resp, err
 	}
-	client := c.newSplunkHttpClient()
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+	resp.Body.Close()
 
-	if err != nil {
-		return err
+	if err = c.renewSessionKey(ctx, sessionKey); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return c.sendRequestWithKey(ctx, method, u, body, contentType, c.currentSessionKey())
 }
 
-func (c *Client) makeGetRestRequest(u *url.URL) (*http.Response, error) {
-	//Create the Request
-	r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%v", u), nil)
-	r.Header.Add("Authorization", "Splunk "+c.SessionKey)
-
-	//Create a client
-	client := c.newSplunkHttpClient()
-	resp, err := client.Do(r)
+// sendRequestWithKey sends a single request authenticated with the
+// Authenticator, or sessionKey if there isn't one.  The body is read from a new
+// reader each time so that a request can be retried.
+func (c *Client) sendRequestWithKey(ctx context.Context, method string,
+	u *url.URL, body []byte, contentType, sessionKey string) (*http.Response, error) {
 
-	if resp.StatusCode != http.StatusOK {
-		return &http.Response{}, errors.New(resp.Status)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
 
+	//Create the Request
+	r, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v", u), reader)
 	if err != nil {
-		return &http.Response{}, err
+		return nil, err
 	}
 
-	return resp, nil
+	if len(contentType) > 0 {
+		r.Header.Add("Content-Type", contentType)
+	}
+
+	var auth Authenticator = SessionKeyAuth(sessionKey)
+	if c.Authenticator != nil {
+		auth = c.Authenticator
+	}
+	if err = auth.Authenticate(r); err != nil {
+		return nil, err
+	}
+
+	return c.httpClient().Do(r)
 }
 
 //buildRequestPath builds a path for the REST request
@@ -233,15 +415,32 @@ func (c *Client) buildRequestPath(pieces []string) (*url.URL, error) {
 	return u, nil
 }
 
-func (c *Client) newSplunkHttpClient() *http.Client {
+// buildEntityPath builds the path of the entity called name in the collection
+// at pieces, followed by any suffix pieces such as "dispatch".  Unlike the
+// pieces the name is escaped, so that names containing a / such as modular
+// input stanza names stay in one segment.
+func (c *Client) buildEntityPath(pieces []string, name string,
+	suffix ...string) (*url.URL, error) {
 
-	//Splunk ships with self signed certificates and these run on a lot of instances
-	// this makes it really hard to do certificate validation
-	tr := &http.Transport{}
+	u, err := c.buildRequestPath(pieces)
+	if err != nil {
+		return u, err
+	}
 
-	if !c.ValidateTLS {
-		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	u.RawPath = u.EscapedPath() + "/" + url.PathEscape(name)
+	u.Path += "/" + name
+	for _, item := range suffix {
+		u.RawPath += "/" + item
+		u.Path += "/" + item
 	}
+	return u, nil
+}
 
-	return &http.Client{Transport: tr}
+// servicesPath prefixes the path with "services" when the client does not have
+// a namespace, buildRequestPath adds servicesNS/<owner>/<namespace> otherwise.
+func (c *Client) servicesPath(pieces ...string) []string {
+	if len(c.Namespace) > 0 {
+		return pieces
+	}
+	return append([]string{"services"}, pieces...)
 }
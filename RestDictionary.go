@@ -0,0 +1,217 @@
+package splunk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Get returns the text value of a key, or an empty string if the dictionary
+// doesn't have it.
+func (dict *RestDictionary) Get(name string) string {
+	value, ok := dict.Lookup(name)
+	if !ok {
+		return ""
+	}
+	return value.String()
+}
+
+// GetBool returns the value of a key as a bool.  Splunk uses both 0/1 and
+// true/false.
+func (dict *RestDictionary) GetBool(name string) (bool, error) {
+	value, ok := dict.Lookup(name)
+	if !ok {
+		return false, errors.New("Key not found: " + name)
+	}
+	return strconv.ParseBool(strings.TrimSpace(value.String()))
+}
+
+// GetInt returns the value of a key as an int.
+func (dict *RestDictionary) GetInt(name string) (int, error) {
+	value, ok := dict.Lookup(name)
+	if !ok {
+		return 0, errors.New("Key not found: " + name)
+	}
+	return strconv.Atoi(strings.TrimSpace(value.String()))
+}
+
+// GetList returns the items of a list value as strings.  A text value is
+// returned as a list with one item, and nil is returned if the key is missing.
+func (dict *RestDictionary) GetList(name string) []string {
+	value, ok := dict.Lookup(name)
+	if !ok {
+		return nil
+	}
+	if !value.IsList() {
+		return []string{value.String()}
+	}
+	return value.Strings()
+}
+
+// Decode sets the fields of the struct pointed to by into from the keys in the
+// dictionary.  Fields are matched to keys by a `splunk:"name"` tag, or by a
+// case insensitive match on the field name if there is no tag.  Fields tagged
+// `splunk:"-"` and keys that are missing or empty are skipped.
+//
+// Fields may be strings, bools, numbers, []string for lists, map[string]string
+// or structs for nested dictionaries, or RestValue to keep the raw value.
+//
+//	type Credential struct {
+//		Username string `splunk:"username"`
+//		Password string `splunk:"clear_password"`
+//		ACL      struct {
+//			Owner string `splunk:"owner"`
+//			Perms struct {
+//				Read []string `splunk:"read"`
+//			} `splunk:"perms"`
+//		} `splunk:"eai:acl"`
+//	}
+func (dict *RestDictionary) Decode(into interface{}) error {
+	target := reflect.ValueOf(into)
+	if target.Kind() != reflect.Ptr || target.IsNil() ||
+		target.Elem().Kind() != reflect.Struct {
+		return errors.New("Decode requires a non-nil pointer to a struct.")
+	}
+	return dict.decodeStruct(target.Elem())
+}
+
+func (dict *RestDictionary) decodeStruct(target reflect.Value) error {
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		name := field.Tag.Get("splunk")
+		if name == "-" {
+			continue
+		}
+
+		var value *RestValue
+		var ok bool
+		if len(name) > 0 {
+			value, ok = dict.Lookup(name)
+		} else {
+			name = field.Name
+			value, ok = dict.lookupFold(name)
+		}
+		if !ok {
+			continue
+		}
+
+		err := setRestValue(target.Field(i), value)
+		if err != nil {
+			return fmt.Errorf("Unable to decode %v into %v: %v", name, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// lookupFold finds a key with a case insensitive match on its name.
+func (dict *RestDictionary) lookupFold(name string) (*RestValue, bool) {
+	for i := range dict.Keys {
+		if strings.EqualFold(dict.Keys[i].Name, name) {
+			return &dict.Keys[i].RestValue, true
+		}
+	}
+	return nil, false
+}
+
+var restValueType = reflect.TypeOf(RestValue{})
+
+func setRestValue(field reflect.Value, value *RestValue) error {
+	if field.Type() == restValueType {
+		field.Set(reflect.ValueOf(*value))
+		return nil
+	}
+
+	text := strings.TrimSpace(value.String())
+
+	switch field.Kind() {
+	case reflect.Ptr:
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setRestValue(field.Elem(), value)
+
+	case reflect.String:
+		field.SetString(value.String())
+
+	case reflect.Bool:
+		if len(text) == 0 {
+			return nil
+		}
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(text) == 0 {
+			return nil
+		}
+		n, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(text) == 0 {
+			return nil
+		}
+		n, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		if len(text) == 0 {
+			return nil
+		}
+		n, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return errors.New("Unsupported slice type " + field.Type().String())
+		}
+		items := value.Strings()
+		if !value.IsList() {
+			if len(text) == 0 {
+				return nil
+			}
+			items = []string{value.String()}
+		}
+		field.Set(reflect.ValueOf(items).Convert(field.Type()))
+
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String ||
+			field.Type().Elem().Kind() != reflect.String {
+			return errors.New("Unsupported map type " + field.Type().String())
+		}
+		if !value.IsDict() {
+			return errors.New("Value is not a dictionary")
+		}
+		field.Set(reflect.ValueOf(value.Dict.Map()).Convert(field.Type()))
+
+	case reflect.Struct:
+		if !value.IsDict() {
+			return errors.New("Value is not a dictionary")
+		}
+		return value.Dict.decodeStruct(field)
+
+	default:
+		return errors.New("Unsupported type " + field.Type().String())
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package splunk
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func decodePasswordContents(t *testing.T) *RestDictionary {
+	resp := &RestResponse{}
+	err := xml.Unmarshal([]byte(passwordRestResponse), resp)
+	if err != nil {
+		t.Fatalf("Unable to decode REST response: %v", err)
+	}
+	return &resp.Entries[0].Contents
+}
+
+func TestRestDictionaryAccessors(t *testing.T) {
+	contents := decodePasswordContents(t)
+
+	if contents.Get("clear_password") != "clear_password_value" {
+		t.Logf("Incorrect clear_password. Received: %v", contents.Get("clear_password"))
+		t.Fail()
+	}
+
+	if contents.Map()["realm"] != "" || len(contents.Map()) != 6 {
+		t.Logf("Incorrect map returned: %v", contents.Map())
+		t.Fail()
+	}
+
+	acl := contents.Keys[1].Dict
+	canWrite, err := acl.GetBool("can_write")
+	if err != nil || !canWrite {
+		t.Logf("Incorrect can_write. Received: %v %v", canWrite, err)
+		t.Fail()
+	}
+
+	modifiable, err := acl.GetInt("modifiable")
+	if err != nil || modifiable != 1 {
+		t.Logf("Incorrect modifiable. Received: %v %v", modifiable, err)
+		t.Fail()
+	}
+
+	if _, err = acl.GetBool("missing"); err == nil {
+		t.Log("Expected an error for a missing key")
+		t.Fail()
+	}
+
+	perms, _ := acl.Lookup("perms")
+	read := perms.Dict.GetList("read")
+	if len(read) != 1 || read[0] != "admin" {
+		t.Logf("Incorrect read list. Received: %v", read)
+		t.Fail()
+	}
+}
+
+func TestRestDictionaryDecode(t *testing.T) {
+	type Credential struct {
+		Username string `splunk:"username"`
+		Password string `splunk:"clear_password"`
+		Realm    string
+		Encr     string `splunk:"-"`
+		ACL      struct {
+			App        string `splunk:"app"`
+			CanWrite   bool   `splunk:"can_write"`
+			Modifiable int    `splunk:"modifiable"`
+			Perms      *struct {
+				Read  []string `splunk:"read"`
+				Write []string `splunk:"write"`
+			} `splunk:"perms"`
+		} `splunk:"eai:acl"`
+	}
+
+	result := &Credential{Encr: "unchanged"}
+	err := decodePasswordContents(t).Decode(result)
+	if err != nil {
+		t.Fatalf("Failed to decode contents: %v", err)
+	}
+
+	if result.Password != "clear_password_value" || result.Encr != "unchanged" {
+		t.Logf("Incorrect credential decoded: %+v", result)
+		t.Fail()
+	}
+
+	if result.ACL.App != "TA-GoogleFitness" || !result.ACL.CanWrite ||
+		result.ACL.Modifiable != 1 {
+		t.Logf("Incorrect ACL decoded: %+v", result.ACL)
+		t.Fail()
+	}
+
+	if result.ACL.Perms == nil || len(result.ACL.Perms.Write) != 1 ||
+		result.ACL.Perms.Write[0] != "admin" {
+		t.Logf("Incorrect perms decoded: %+v", result.ACL.Perms)
+		t.Fail()
+	}
+
+	bad := struct {
+		Password int `splunk:"clear_password"`
+	}{}
+	if err = decodePasswordContents(t).Decode(&bad); err == nil {
+		t.Log("Expected an error decoding text into an int")
+		t.Fail()
+	}
+}
@@ -1,15 +1,31 @@
 package splunk
 
+import "encoding/xml"
+
 type SessionKey struct {
 	SessionKey string `xml:"sessionKey"`
 	Message    string `xml:"messages>msg"`
 }
 
 type RestResponse struct {
-	Title   string      `xml:"title"`
-	Id      string      `xml:"id"`
-	Updated string      `xml:"updated"`
-	Entries []RestEntry `xml:"entry"`
+	Title   string `xml:"title"`
+	Id      string `xml:"id"`
+	Updated string `xml:"updated"`
+
+	// Paging information
+	TotalResults int `xml:"http://a9.com/-/spec/opensearch/1.1/ totalResults"`
+	ItemsPerPage int `xml:"http://a9.com/-/spec/opensearch/1.1/ itemsPerPage"`
+	StartIndex   int `xml:"http://a9.com/-/spec/opensearch/1.1/ startIndex"`
+
+	Messages []RestMessage `xml:"messages>msg"`
+	Link     []RestLink    `xml:"link"`
+	Entries  []RestEntry   `xml:"entry"`
+}
+
+// LinkHref returns the href of the feed link with the given rel, such as
+// create, or an empty string if there isn't one.
+func (resp *RestResponse) LinkHref(rel string) string {
+	return findLinkHref(resp.Link, rel)
 }
 
 func (resp *RestResponse) string() string {
@@ -24,21 +40,163 @@ type RestEntry struct {
 	Title    string         `xml:"title"`
 	ID       string         `xml:"id"`
 	Updated  string         `xml:"updated"`
-	Link     []string       `xml:"link"`
+	Link     []RestLink     `xml:"link"`
 	Author   string         `xml:"author"`
 	Contents RestDictionary `xml:"content>dict"`
 }
 
-//TODO: add a map[sting]string to get easier access to values.
+// LinkHref returns the href of the entry link with the given rel, such as edit
+// or remove, or an empty string if there isn't one.
+func (entry *RestEntry) LinkHref(rel string) string {
+	return findLinkHref(entry.Link, rel)
+}
+
+// RestLink is a link to a related endpoint.  Rel describes what the link is for,
+// e.g. create, edit, remove or list.
+type RestLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+func findLinkHref(links []RestLink, rel string) string {
+	for _, link := range links {
+		if link.Rel == rel {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// RestDictionary is the s:dict of keys in the content of an entry.
 type RestDictionary struct {
 	Keys []RestKey `xml:"key"`
 }
 
+// Map returns the keys in the dictionary as a map of names to text values.
+func (dict *RestDictionary) Map() map[string]string {
+	result := make(map[string]string)
+	for _, key := range dict.Keys {
+		result[key.Name] = key.Value
+	}
+	return result
+}
+
+// UnmarshalXML decodes an s:dict, removing the whitespace that surrounds
+// nested values.
+func (dict *RestDictionary) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type restDictionary RestDictionary
+	err := d.DecodeElement((*restDictionary)(dict), &start)
+	if err != nil {
+		return err
+	}
+
+	for i := range dict.Keys {
+		dict.Keys[i].clearNestedText()
+	}
+	return nil
+}
+
+// Lookup follows the names through nested dictionaries and returns the value
+// at the end, e.g. dict.Lookup("eai:acl", "perms", "read").
+func (dict *RestDictionary) Lookup(names ...string) (*RestValue, bool) {
+	if dict == nil || len(names) == 0 {
+		return nil, false
+	}
+
+	for i := range dict.Keys {
+		if dict.Keys[i].Name != names[0] {
+			continue
+		}
+
+		value := &dict.Keys[i].RestValue
+		if len(names) == 1 {
+			return value, true
+		}
+		return value.Dict.Lookup(names[1:]...)
+	}
+	return nil, false
+}
+
 type RestKey struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:",chardata"`
+	Name string `xml:"name,attr"`
+	RestValue
+}
+
+// RestValue is a value in a REST response.  It is either text, a nested
+// dictionary (s:dict) or a list (s:list) of further values.
+type RestValue struct {
+	Value string          `xml:",chardata"`
+	Dict  *RestDictionary `xml:"dict"`
+	List  *RestList       `xml:"list"`
+}
+
+// RestList is a list of values in a REST response.
+type RestList struct {
+	Items []RestValue `xml:"item"`
+}
+
+// UnmarshalXML decodes an s:list, removing the whitespace that surrounds
+// nested values.
+func (list *RestList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type restList RestList
+	err := d.DecodeElement((*restList)(list), &start)
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		list.Items[i].clearNestedText()
+	}
+	return nil
+}
+
+// IsDict returns true if the value is a nested dictionary.
+func (value *RestValue) IsDict() bool {
+	return value.Dict != nil
+}
+
+// IsList returns true if the value is a list.
+func (value *RestValue) IsList() bool {
+	return value.List != nil
+}
+
+// String returns the text of the value, which is empty for a dictionary or
+// list.
+func (value *RestValue) String() string {
+	return value.Value
+}
+
+// Items returns the values in a list, or nil if the value is not a list.
+func (value *RestValue) Items() []RestValue {
+	if value.List == nil {
+		return nil
+	}
+	return value.List.Items
+}
+
+// Strings returns the text of each value in a list.
+func (value *RestValue) Strings() []string {
+	result := []string{}
+	for _, item := range value.Items() {
+		result = append(result, item.Value)
+	}
+	return result
+}
+
+// clearNestedText removes the whitespace between the tags of a nested
+// dictionary or list, which encoding/xml collects as chardata.
+func (value *RestValue) clearNestedText() {
+	if value.Dict != nil || value.List != nil {
+		value.Value = ""
+	}
 }
 
 func (key *RestKey) GoString() string {
 	return "Name: " + key.Name + "Value: " + key.Value
 }
+
+// RestMessage is a message returned by Splunk, usually to describe an error.
+type RestMessage struct {
+	Type string `xml:"type,attr" json:"type"`
+	Text string `xml:",chardata" json:"text"`
+}
@@ -0,0 +1,136 @@
+package splunk
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// jsonRestResponse is the envelope Splunk returns with output_mode=json.
+type jsonRestResponse struct {
+	Origin   string            `json:"origin"`
+	Updated  string            `json:"updated"`
+	Links    map[string]string `json:"links"`
+	Entries  []jsonRestEntry   `json:"entry"`
+	Messages []RestMessage     `json:"messages"`
+	Paging   struct {
+		Total   int `json:"total"`
+		PerPage int `json:"perPage"`
+		Offset  int `json:"offset"`
+	} `json:"paging"`
+}
+
+type jsonRestEntry struct {
+	Name    string                 `json:"name"`
+	ID      string                 `json:"id"`
+	Updated string                 `json:"updated"`
+	Links   map[string]string      `json:"links"`
+	Author  string                 `json:"author"`
+	ACL     map[string]interface{} `json:"acl"`
+	Content map[string]interface{} `json:"content"`
+}
+
+// decodeJSONRestResponse decodes Splunk's JSON envelope into the same
+// RestResponse that is decoded from Atom XML.  The entry acl is added to the
+// content as eai:acl, as it is in XML.
+func decodeJSONRestResponse(r io.Reader) (*RestResponse, error) {
+	body := &jsonRestResponse{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	err := decoder.Decode(body)
+	if err == io.EOF {
+		return &RestResponse{}, nil
+	}
+	if err != nil {
+		return &RestResponse{}, err
+	}
+
+	result := &RestResponse{
+		Id:           body.Origin,
+		Updated:      body.Updated,
+		TotalResults: body.Paging.Total,
+		ItemsPerPage: body.Paging.PerPage,
+		StartIndex:   body.Paging.Offset,
+		Messages:     body.Messages,
+		Link:         jsonRestLinks(body.Links),
+	}
+
+	for _, entry := range body.Entries {
+		content := entry.Content
+		if entry.ACL != nil {
+			if content == nil {
+				content = make(map[string]interface{})
+			}
+			content["eai:acl"] = entry.ACL
+		}
+
+		result.Entries = append(result.Entries, RestEntry{
+			Title:    entry.Name,
+			ID:       entry.ID,
+			Updated:  entry.Updated,
+			Link:     jsonRestLinks(entry.Links),
+			Author:   entry.Author,
+			Contents: *jsonRestDictionary(content),
+		})
+	}
+
+	return result, nil
+}
+
+func jsonRestLinks(links map[string]string) []RestLink {
+	rels := []string{}
+	for rel := range links {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	result := []RestLink{}
+	for _, rel := range rels {
+		result = append(result, RestLink{Href: links[rel], Rel: rel})
+	}
+	return result
+}
+
+// jsonRestDictionary converts a JSON object into a RestDictionary with the keys
+// sorted by name.
+func jsonRestDictionary(object map[string]interface{}) *RestDictionary {
+	names := []string{}
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &RestDictionary{}
+	for _, name := range names {
+		result.Keys = append(result.Keys, RestKey{
+			Name:      name,
+			RestValue: jsonRestValue(object[name]),
+		})
+	}
+	return result
+}
+
+func jsonRestValue(value interface{}) RestValue {
+	switch v := value.(type) {
+	case nil:
+		return RestValue{}
+	case string:
+		return RestValue{Value: v}
+	case json.Number:
+		return RestValue{Value: v.String()}
+	case bool:
+		return RestValue{Value: strconv.FormatBool(v)}
+	case map[string]interface{}:
+		return RestValue{Dict: jsonRestDictionary(v)}
+	case []interface{}:
+		list := &RestList{Items: []RestValue{}}
+		for _, item := range v {
+			list.Items = append(list.Items, jsonRestValue(item))
+		}
+		return RestValue{List: list}
+	}
+
+	b, _ := json.Marshal(value)
+	return RestValue{Value: string(b)}
+}
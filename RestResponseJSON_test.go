@@ -0,0 +1,97 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const passwordJSONResponse string = `{
+  "links": {"create": "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_new",
+            "_reload": "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_reload"},
+  "origin": "https://localhost:8089/servicesNS/nobody/TA-GoogleFitness/storage/passwords",
+  "updated": "2016-07-18T22:00:32-07:00",
+  "generator": {"build": "debde650d26e", "version": "6.4.1"},
+  "entry": [{
+    "name": ":bob:",
+    "id": "https://localhost:8089/servicesNS/nobody/TA-GoogleFitness/storage/passwords/%3Abob%3A",
+    "updated": "2016-07-18T22:00:32-07:00",
+    "links": {"alternate": "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/%3Abob%3A",
+              "remove": "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/%3Abob%3A"},
+    "author": "admin",
+    "acl": {"app": "TA-GoogleFitness", "can_write": true, "owner": "admin",
+            "perms": {"read": ["admin", "power"], "write": ["admin"]}},
+    "content": {"clear_password": "hunter2", "realm": null, "username": "bob",
+                "eai:acl": null, "priority": 5}
+  }],
+  "paging": {"total": 31, "perPage": 30, "offset": 0},
+  "messages": []
+}`
+
+func TestJSONOutputMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("output_mode") != "json" {
+			t.Errorf("Expected output_mode=json: %v", r.URL.RawQuery)
+		}
+		w.Write([]byte(passwordJSONResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{
+		BaseURL:    server.URL,
+		Owner:      "nobody",
+		Namespace:  "TA-GoogleFitness",
+		OutputMode: OutputModeJSON,
+	}
+
+	resp, err := c.GetEntities([]string{"storage", "passwords"})
+	if err != nil {
+		t.Fatalf("Failed to get entities: %v", err)
+	}
+
+	if resp.TotalResults != 31 || resp.ItemsPerPage != 30 {
+		t.Logf("Incorrect paging returned: %v %v", resp.TotalResults, resp.ItemsPerPage)
+		t.Fail()
+	}
+
+	if resp.LinkHref("create") != "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_new" {
+		t.Logf("Incorrect create link: %v", resp.Link)
+		t.Fail()
+	}
+
+	entry := resp.Entries[0]
+	if entry.Title != ":bob:" || entry.Author != "admin" ||
+		entry.LinkHref("remove") == "" {
+		t.Logf("Incorrect entry returned: %+v", entry)
+		t.Fail()
+	}
+
+	if entry.Contents.Get("clear_password") != "hunter2" ||
+		entry.Contents.Get("priority") != "5" {
+		t.Logf("Incorrect contents returned: %v", entry.Contents.Map())
+		t.Fail()
+	}
+
+	acl, ok := entry.Contents.Lookup("eai:acl")
+	if !ok || !acl.IsDict() {
+		t.Fatalf("Expected the acl as eai:acl: %v", entry.Contents.Map())
+	}
+
+	canWrite, err := acl.Dict.GetBool("can_write")
+	if err != nil || !canWrite {
+		t.Logf("Incorrect can_write: %v %v", canWrite, err)
+		t.Fail()
+	}
+
+	read, _ := entry.Contents.Lookup("eai:acl", "perms", "read")
+	if read == nil || len(read.Strings()) != 2 {
+		t.Logf("Incorrect read permissions: %#v", read)
+		t.Fail()
+	}
+
+	credential, err := c.GetCredential("", "bob")
+	if err != nil || credential.ClearPassword != "hunter2" {
+		t.Logf("Incorrect credential returned: %v %v", credential, err)
+		t.Fail()
+	}
+}
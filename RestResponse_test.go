@@ -232,3 +232,89 @@ func TestDecodePasswordStore(t *testing.T) {
 		t.Fatalf("Failed to unmarshal input. %v", err)
 	}
 }
+
+func TestRestUnmarshalNested(t *testing.T) {
+	resp := &RestResponse{}
+	err := xml.Unmarshal([]byte(passwordRestResponse), resp)
+	if err != nil {
+		t.Fatalf("Unable to decode REST response: %v", err)
+	}
+
+	contents := resp.Entries[0].Contents
+	acl, ok := contents.Lookup("eai:acl")
+	if !ok || !acl.IsDict() {
+		t.Fatalf("Expected eai:acl to be a dictionary: %#v", acl)
+	}
+
+	if acl.String() != "" {
+		t.Logf("Expected no text for a nested dictionary. Received: %q", acl.String())
+		t.Fail()
+	}
+
+	owner, ok := contents.Lookup("eai:acl", "owner")
+	if !ok || owner.String() != "admin" {
+		t.Logf("Incorrect owner returned: %#v", owner)
+		t.Fail()
+	}
+
+	read, ok := contents.Lookup("eai:acl", "perms", "read")
+	if !ok || !read.IsList() {
+		t.Fatalf("Expected perms.read to be a list: %#v", read)
+	}
+
+	if strings.Join(read.Strings(), ",") != "admin" {
+		t.Logf("Incorrect read permissions. Expected: admin Received: %v", read.Strings())
+		t.Fail()
+	}
+
+	if _, ok = contents.Lookup("eai:acl", "missing"); ok {
+		t.Log("Expected a missing key not to be found")
+		t.Fail()
+	}
+}
+
+func TestRestUnmarshalFeedMetadata(t *testing.T) {
+	input := `<?xml-stylesheet type="text/xml" href="/static/atom.xsl"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+  <title>passwords</title>
+  <link href="/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_new" rel="create"/>
+  <link href="/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_reload" rel="_reload"/>
+  <opensearch:totalResults>45</opensearch:totalResults>
+  <opensearch:itemsPerPage>30</opensearch:itemsPerPage>
+  <opensearch:startIndex>30</opensearch:startIndex>
+  <s:messages>
+    <s:msg type="WARN">Some entries were hidden.</s:msg>
+  </s:messages>
+</feed>`
+
+	resp := &RestResponse{}
+	err := xml.Unmarshal([]byte(input), resp)
+	if err != nil {
+		t.Fatalf("Unable to decode REST response: %v", err)
+	}
+
+	if resp.TotalResults != 45 || resp.ItemsPerPage != 30 || resp.StartIndex != 30 {
+		t.Logf("Incorrect paging returned: %v %v %v",
+			resp.TotalResults, resp.ItemsPerPage, resp.StartIndex)
+		t.Fail()
+	}
+
+	if len(resp.Messages) != 1 || resp.Messages[0].Type != "WARN" ||
+		resp.Messages[0].Text != "Some entries were hidden." {
+		t.Logf("Incorrect messages returned: %v", resp.Messages)
+		t.Fail()
+	}
+
+	if resp.LinkHref("create") != "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/_new" {
+		t.Logf("Incorrect create link returned: %v", resp.LinkHref("create"))
+		t.Fail()
+	}
+
+	full := &RestResponse{}
+	xml.Unmarshal([]byte(passwordRestResponse), full)
+	remove := full.Entries[0].LinkHref("remove")
+	if remove != "/servicesNS/nobody/TA-GoogleFitness/storage/passwords/%3A616872666934-ctkc2btlhme0or0vmar8mlaidt2g1j16.apps.googleusercontent.com%3A" {
+		t.Logf("Incorrect remove link returned: %v", remove)
+		t.Fail()
+	}
+}
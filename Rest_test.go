@@ -47,3 +47,37 @@ func TestBuildPath(t *testing.T) {
 			"Received: %v:\n", result)
 	}
 }
+
+func TestBuildPathEscaping(t *testing.T) {
+	c := &Client{
+		BaseURL:   LocalSplunkMgmntURL,
+		Owner:     "nobody",
+		Namespace: "TA-S3",
+	}
+	result, err := c.buildEntityPath([]string{"data", "inputs", "s3"}, "bucket/some dir")
+	if err != nil {
+		t.Fatalf("Error building path: %v", err)
+	}
+
+	expected := "https://localhost:8089/servicesNS/nobody/TA-S3/data/inputs/s3/bucket%2Fsome%20dir"
+	if fmt.Sprintf("%v", result) != expected {
+		t.Fatalf("Failed to escape entity name.\n"+
+			"Expected: %v\n"+
+			"Received: %v\n", expected, result)
+	}
+
+	// Path pieces are not escaped, so that a piece may hold several segments.
+	c.Namespace = ""
+	c.Owner = ""
+	result, err = c.buildRequestPath([]string{"services", "saved/searches"})
+	if err != nil {
+		t.Fatalf("Error building path: %v", err)
+	}
+
+	expected = "https://localhost:8089/services/saved/searches"
+	if fmt.Sprintf("%v", result) != expected {
+		t.Fatalf("Path pieces should not be escaped.\n"+
+			"Expected: %v\n"+
+			"Received: %v\n", expected, result)
+	}
+}
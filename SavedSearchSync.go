@@ -0,0 +1,267 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type SavedSearchAction string
+
+const SavedSearchCreate = "create"
+const SavedSearchUpdate = "update"
+const SavedSearchDelete = "delete"
+
+// SavedSearchChange is a single change required to bring a saved search on the
+// server in line with its definition.
+type SavedSearchChange struct {
+	Action SavedSearchAction
+	Name   string
+
+	// Desired is the definition of the saved search, nil when deleting.
+	Desired *SavedSearch
+	// Current is the saved search on the server, nil when creating.
+	Current *SavedSearch
+	// Settings are the settings that differ when updating.
+	Settings []SavedSearchSettingChange
+}
+
+// SavedSearchSettingChange is a setting that differs between the definition of
+// a saved search and the server.
+type SavedSearchSettingChange struct {
+	Name    string
+	Current string
+	Desired string
+}
+
+// SavedSearchPlan is the set of changes required to reconcile saved search
+// definitions with the server.
+type SavedSearchPlan struct {
+	Changes []*SavedSearchChange
+}
+
+// ReadSavedSearchDefinitions decodes a JSON array of saved search definitions,
+// using the same keys as savedsearches.conf, e.g. cron_schedule.  The
+// savedsearchsync command also reads YAML definitions with the same keys.
+func ReadSavedSearchDefinitions(r io.Reader) ([]*SavedSearch, error) {
+	result := []*SavedSearch{}
+	decoder := json.NewDecoder(r)
+	err := decoder.Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, search := range result {
+		if len(search.Name) == 0 {
+			return nil, errors.New("Saved search definition is missing a name.")
+		}
+	}
+	return result, nil
+}
+
+// PlanSavedSearches compares the desired saved searches with the saved searches
+// in the client's namespace and returns the changes needed to reconcile them.
+// If prune is true saved searches that are not in desired are deleted, which
+// requires the client to have a namespace.
+func (c *Client) PlanSavedSearches(desired []*SavedSearch,
+	prune bool) (*SavedSearchPlan, error) {
+	return c.PlanSavedSearchesContext(context.Background(), desired, prune)
+}
+
+// PlanSavedSearchesContext is PlanSavedSearches with a context that cancels the
+// request.
+func (c *Client) PlanSavedSearchesContext(ctx context.Context,
+	desired []*SavedSearch, prune bool) (*SavedSearchPlan, error) {
+
+	if prune && len(c.Namespace) == 0 {
+		return nil, errors.New("A namespace is required to prune saved searches.")
+	}
+
+	existing, err := c.ListSavedSearchesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]*SavedSearch)
+	for _, search := range existing {
+		if len(c.Namespace) > 0 && appFromEntityID(search.ID) != c.Namespace {
+			continue
+		}
+		current[search.Name] = search
+	}
+
+	plan := &SavedSearchPlan{}
+	seen := make(map[string]bool)
+	for _, search := range desired {
+		if seen[search.Name] {
+			return nil, errors.New("Duplicate saved search definition: " + search.Name)
+		}
+		seen[search.Name] = true
+
+		server, ok := current[search.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, &SavedSearchChange{
+				Action:  SavedSearchCreate,
+				Name:    search.Name,
+				Desired: search,
+			})
+			continue
+		}
+
+		settings := diffSavedSearch(server, search)
+		if len(settings) > 0 {
+			plan.Changes = append(plan.Changes, &SavedSearchChange{
+				Action:   SavedSearchUpdate,
+				Name:     search.Name,
+				Desired:  search,
+				Current:  server,
+				Settings: settings,
+			})
+		}
+	}
+
+	if prune {
+		names := []string{}
+		for name := range current {
+			if !seen[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			plan.Changes = append(plan.Changes, &SavedSearchChange{
+				Action:  SavedSearchDelete,
+				Name:    name,
+				Current: current[name],
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplySavedSearchPlan makes the changes in the plan on the server.  It stops
+// at the first change that fails.
+func (c *Client) ApplySavedSearchPlan(plan *SavedSearchPlan) error {
+	return c.ApplySavedSearchPlanContext(context.Background(), plan)
+}
+
+// ApplySavedSearchPlanContext is ApplySavedSearchPlan with a context that
+// cancels the requests.
+func (c *Client) ApplySavedSearchPlanContext(ctx context.Context,
+	plan *SavedSearchPlan) error {
+
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case SavedSearchCreate:
+			_, err = c.CreateSavedSearchContext(ctx, change.Desired)
+		case SavedSearchUpdate:
+			_, err = c.UpdateSavedSearchContext(ctx, change.Desired)
+		case SavedSearchDelete:
+			err = c.DeleteSavedSearchContext(ctx, change.Name)
+		default:
+			err = errors.New("Unknown action: " + string(change.Action))
+		}
+
+		if err != nil {
+			return fmt.Errorf("Unable to %v saved search %q: %w",
+				change.Action, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// String describes the plan for a dry run.
+func (plan *SavedSearchPlan) String() string {
+	if len(plan.Changes) == 0 {
+		return "No changes. Saved searches are up to date.\n"
+	}
+
+	counts := make(map[SavedSearchAction]int)
+	result := ""
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+
+		switch change.Action {
+		case SavedSearchCreate:
+			result += fmt.Sprintf("+ create %q\n", change.Name)
+		case SavedSearchUpdate:
+			result += fmt.Sprintf("~ update %q\n", change.Name)
+			for _, setting := range change.Settings {
+				result += fmt.Sprintf("    %v: %q => %q\n",
+					setting.Name, setting.Current, setting.Desired)
+			}
+		case SavedSearchDelete:
+			result += fmt.Sprintf("- delete %q\n", change.Name)
+		}
+	}
+
+	result += fmt.Sprintf("Plan: %v to create, %v to update, %v to delete.\n",
+		counts[SavedSearchCreate], counts[SavedSearchUpdate],
+		counts[SavedSearchDelete])
+	return result
+}
+
+// diffSavedSearch returns the settings in desired that differ from current.
+func diffSavedSearch(current, desired *SavedSearch) []SavedSearchSettingChange {
+	currentParams := current.params()
+	desiredParams := desired.params()
+
+	names := []string{}
+	for name := range desiredParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []SavedSearchSettingChange{}
+	for _, name := range names {
+		want := desiredParams.Get(name)
+		have := currentParams.Get(name)
+		if _, ok := currentParams[name]; !ok {
+			have = current.Content[name]
+		}
+
+		if !savedSearchValuesEqual(have, want) {
+			result = append(result, SavedSearchSettingChange{
+				Name:    name,
+				Current: have,
+				Desired: want,
+			})
+		}
+	}
+	return result
+}
+
+// savedSearchValuesEqual compares settings, treating the different ways Splunk
+// represents booleans as equal.
+func savedSearchValuesEqual(current, desired string) bool {
+	if current == desired {
+		return true
+	}
+	if desired == "true" || desired == "false" {
+		return parseSplunkBool(current) == parseSplunkBool(desired)
+	}
+	return strings.TrimSpace(current) == strings.TrimSpace(desired)
+}
+
+// appFromEntityID returns the app from the id of an entity in the form
+// https://host:8089/servicesNS/<owner>/<app>/...
+func appFromEntityID(id string) string {
+	u, err := url.Parse(id)
+	if err != nil {
+		return ""
+	}
+
+	pieces := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(pieces) < 3 || pieces[0] != "servicesNS" {
+		return ""
+	}
+	return pieces[2]
+}
@@ -0,0 +1,181 @@
+package splunk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const savedSearchSyncResponse string = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">
+  <title>savedsearches</title>
+  <entry>
+    <title>errors</title>
+    <id>https://localhost:8089/servicesNS/nobody/ops/saved/searches/errors</id>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="cron_schedule">*/5 * * * *</s:key>
+        <s:key name="disabled">0</s:key>
+        <s:key name="is_scheduled">1</s:key>
+        <s:key name="search">error | stats count</s:key>
+      </s:dict>
+    </content>
+  </entry>
+  <entry>
+    <title>unchanged</title>
+    <id>https://localhost:8089/servicesNS/nobody/ops/saved/searches/unchanged</id>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="disabled">0</s:key>
+        <s:key name="is_scheduled">0</s:key>
+        <s:key name="search">index=main</s:key>
+      </s:dict>
+    </content>
+  </entry>
+  <entry>
+    <title>old</title>
+    <id>https://localhost:8089/servicesNS/nobody/ops/saved/searches/old</id>
+    <content type="text/xml">
+      <s:dict><s:key name="search">index=old</s:key></s:dict>
+    </content>
+  </entry>
+  <entry>
+    <title>other app</title>
+    <id>https://localhost:8089/servicesNS/nobody/search/saved/searches/other%20app</id>
+    <content type="text/xml">
+      <s:dict><s:key name="search">index=other</s:key></s:dict>
+    </content>
+  </entry>
+</feed>`
+
+const savedSearchDefinitions = `[
+  {"name": "errors", "search": "error | stats count", "is_scheduled": true,
+   "cron_schedule": "*/10 * * * *"},
+  {"name": "unchanged", "search": "index=main"},
+  {"name": "new", "search": "index=new"}
+]`
+
+func TestPlanSavedSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(savedSearchSyncResponse))
+	}))
+	defer server.Close()
+
+	desired, err := ReadSavedSearchDefinitions(strings.NewReader(savedSearchDefinitions))
+	if err != nil {
+		t.Fatalf("Failed to read saved search definitions: %v", err)
+	}
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "ops"}
+	plan, err := c.PlanSavedSearches(desired, true)
+	if err != nil {
+		t.Fatalf("Failed to plan saved searches: %v", err)
+	}
+
+	expected := `~ update "errors"
+    cron_schedule: "*/5 * * * *" => "*/10 * * * *"
++ create "new"
+- delete "old"
+Plan: 1 to create, 1 to update, 1 to delete.
+`
+	if plan.String() != expected {
+		t.Logf("Incorrect plan.\nExpected:\n%v\nReceived:\n%v", expected, plan)
+		t.Fail()
+	}
+}
+
+func TestApplySavedSearchPlanAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<response><messages><msg type="ERROR">Permission denied</msg></messages></response>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "ops"}
+	plan := &SavedSearchPlan{Changes: []*SavedSearchChange{
+		{Action: SavedSearchDelete, Name: "old"},
+	}}
+
+	err := c.ApplySavedSearchPlan(plan)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Logf("Expected an APIError to be wrapped, received: %v", err)
+		t.Fail()
+	}
+}
+
+func TestApplySavedSearchPlanContextCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "ops"}
+	plan := &SavedSearchPlan{Changes: []*SavedSearchChange{
+		{Action: SavedSearchDelete, Name: "old"},
+	}}
+
+	err := c.ApplySavedSearchPlanContext(ctx, plan)
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected context.Canceled, received: %v", err)
+		t.Fail()
+	}
+
+	if requests != 0 {
+		t.Logf("Cancelled requests reached the server: %v", requests)
+		t.Fail()
+	}
+}
+
+func TestApplySavedSearchPlan(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		request := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/servicesNS/nobody/ops")
+		if r.Method == http.MethodPost {
+			request += " " + r.PostForm.Get("name") + " " + r.PostForm.Get("cron_schedule")
+		}
+		requests = append(requests, strings.TrimSpace(request))
+
+		if r.Method == http.MethodDelete {
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+			return
+		}
+		w.Write([]byte(savedSearchSyncResponse))
+	}))
+	defer server.Close()
+
+	desired, err := ReadSavedSearchDefinitions(strings.NewReader(savedSearchDefinitions))
+	if err != nil {
+		t.Fatalf("Failed to read saved search definitions: %v", err)
+	}
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "ops"}
+	plan, err := c.PlanSavedSearches(desired, true)
+	if err != nil {
+		t.Fatalf("Failed to plan saved searches: %v", err)
+	}
+
+	if err = c.ApplySavedSearchPlan(plan); err != nil {
+		t.Fatalf("Failed to apply plan: %v", err)
+	}
+
+	expected := []string{
+		"GET /saved/searches",
+		"POST /saved/searches/errors  */10 * * * *",
+		"POST /saved/searches new",
+		"DELETE /saved/searches/old",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %q\nReceived: %q", expected, requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,394 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SavedSearch is a search saved in Splunk along with its schedule and alert
+// settings.
+type SavedSearch struct {
+	Name        string `json:"name" yaml:"name"`
+	Search      string `json:"search" yaml:"search"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+
+	// Schedule settings
+	IsScheduled  bool   `json:"is_scheduled,omitempty" yaml:"is_scheduled,omitempty"`
+	CronSchedule string `json:"cron_schedule,omitempty" yaml:"cron_schedule,omitempty"`
+	EarliestTime string `json:"dispatch.earliest_time,omitempty" yaml:"dispatch.earliest_time,omitempty"`
+	LatestTime   string `json:"dispatch.latest_time,omitempty" yaml:"dispatch.latest_time,omitempty"`
+
+	// Alert settings
+	AlertType           string `json:"alert_type,omitempty" yaml:"alert_type,omitempty"`
+	AlertComparator     string `json:"alert_comparator,omitempty" yaml:"alert_comparator,omitempty"`
+	AlertThreshold      string `json:"alert_threshold,omitempty" yaml:"alert_threshold,omitempty"`
+	AlertCondition      string `json:"alert_condition,omitempty" yaml:"alert_condition,omitempty"`
+	AlertSeverity       int    `json:"alert.severity,omitempty" yaml:"alert.severity,omitempty"`
+	AlertSuppress       bool   `json:"alert.suppress,omitempty" yaml:"alert.suppress,omitempty"`
+	AlertSuppressPeriod string `json:"alert.suppress.period,omitempty" yaml:"alert.suppress.period,omitempty"`
+	AlertTrack          bool   `json:"alert.track,omitempty" yaml:"alert.track,omitempty"`
+	Actions             string `json:"actions,omitempty" yaml:"actions,omitempty"`
+
+	// Settings holds any other writable settings such as action.email.to or
+	// display.general.type.  They are sent when the saved search is created or
+	// updated.
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+
+	// ID is the URL of the saved search returned by Splunk.
+	ID string `json:"-" yaml:"-"`
+
+	// Content holds every value Splunk returned for the saved search, including
+	// read only values such as next_scheduled_time.  It is not sent to Splunk.
+	Content map[string]string `json:"-" yaml:"-"`
+}
+
+// savedSearchSettingPrefixes are the prefixes of writable saved search settings
+// that are not mapped to a field on SavedSearch.
+var savedSearchSettingPrefixes = []string{
+	"action.", "alert.", "args.", "auto_summarize", "dispatch.", "display.",
+	"request.",
+}
+
+// savedSearchFields are the settings that are mapped to fields on SavedSearch.
+var savedSearchFields = map[string]bool{
+	"search": true, "description": true, "disabled": true, "is_scheduled": true,
+	"cron_schedule": true, "dispatch.earliest_time": true,
+	"dispatch.latest_time": true, "alert_type": true, "alert_comparator": true,
+	"alert_threshold": true, "alert_condition": true, "alert.severity": true,
+	"alert.suppress": true, "alert.suppress.period": true, "alert.track": true,
+	"actions": true,
+}
+
+// DispatchOptions are used to override the settings of a saved search when it
+// is dispatched.
+type DispatchOptions struct {
+	// Args replace $args.<name>$ tokens in the saved search.
+	Args map[string]string
+
+	EarliestTime   string
+	LatestTime     string
+	Now            string
+	TriggerActions bool
+	ForceDispatch  bool
+}
+
+// SavedSearchJob is a job from the dispatch history of a saved search.
+type SavedSearchJob struct {
+	SID              string
+	Updated          string
+	IsDone           bool
+	IsFailed         bool
+	IsScheduled      bool
+	IsRealTimeSearch bool
+	IsZombie         bool
+	Content          map[string]string
+}
+
+// ListSavedSearches returns all of the saved searches visible to the client.
+func (c *Client) ListSavedSearches() ([]*SavedSearch, error) {
+	return c.ListSavedSearchesContext(context.Background())
+}
+
+// ListSavedSearchesContext is ListSavedSearches with a context that cancels the
+// request.
+func (c *Client) ListSavedSearchesContext(ctx context.Context) ([]*SavedSearch, error) {
+	params := url.Values{}
+	params.Set("count", "0")
+
+	resp, err := c.entityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("saved", "searches"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*SavedSearch{}
+	for _, entry := range resp.Entries {
+		result = append(result, newSavedSearchFromEntry(entry))
+	}
+	return result, nil
+}
+
+// GetSavedSearch returns a single saved search by name.
+func (c *Client) GetSavedSearch(name string) (*SavedSearch, error) {
+	return c.GetSavedSearchContext(context.Background(), name)
+}
+
+// GetSavedSearchContext is GetSavedSearch with a context that cancels the
+// request.
+func (c *Client) GetSavedSearchContext(ctx context.Context,
+	name string) (*SavedSearch, error) {
+
+	resp, err := c.namedEntityRequestContext(ctx, http.MethodGet,
+		c.servicesPath("saved", "searches"), name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return firstSavedSearch(resp)
+}
+
+// CreateSavedSearch creates a new saved search and returns it as stored by
+// Splunk.
+func (c *Client) CreateSavedSearch(search *SavedSearch) (*SavedSearch, error) {
+	return c.CreateSavedSearchContext(context.Background(), search)
+}
+
+// CreateSavedSearchContext is CreateSavedSearch with a context that cancels the
+// request.
+func (c *Client) CreateSavedSearchContext(ctx context.Context,
+	search *SavedSearch) (*SavedSearch, error) {
+
+	if len(search.Name) == 0 {
+		return nil, errors.New("Saved search must have a name.")
+	}
+
+	params := search.params()
+	params.Set("name", search.Name)
+
+	resp, err := c.entityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("saved", "searches"), params)
+	if err != nil {
+		return nil, err
+	}
+	return firstSavedSearch(resp)
+}
+
+// UpdateSavedSearch updates an existing saved search with the settings in
+// search and returns it as stored by Splunk.
+func (c *Client) UpdateSavedSearch(search *SavedSearch) (*SavedSearch, error) {
+	return c.UpdateSavedSearchContext(context.Background(), search)
+}
+
+// UpdateSavedSearchContext is UpdateSavedSearch with a context that cancels the
+// request.
+func (c *Client) UpdateSavedSearchContext(ctx context.Context,
+	search *SavedSearch) (*SavedSearch, error) {
+
+	resp, err := c.namedEntityRequestContext(ctx, http.MethodPost,
+		c.servicesPath("saved", "searches"), search.Name, search.params())
+	if err != nil {
+		return nil, err
+	}
+	return firstSavedSearch(resp)
+}
+
+// DeleteSavedSearch deletes a saved search by name.
+func (c *Client) DeleteSavedSearch(name string) error {
+	return c.DeleteSavedSearchContext(context.Background(), name)
+}
+
+// DeleteSavedSearchContext is DeleteSavedSearch with a context that cancels the
+// request.
+func (c *Client) DeleteSavedSearchContext(ctx context.Context, name string) error {
+	_, err := c.namedEntityRequestContext(ctx, http.MethodDelete,
+		c.servicesPath("saved", "searches"), name, nil)
+	return err
+}
+
+// DispatchSavedSearch runs a saved search and returns the sid of the new job.
+// opts may be nil to run the search with its saved settings.
+func (c *Client) DispatchSavedSearch(name string, opts *DispatchOptions) (string, error) {
+	return c.DispatchSavedSearchContext(context.Background(), name, opts)
+}
+
+// DispatchSavedSearchContext is DispatchSavedSearch with a context that cancels
+// the request.
+func (c *Client) DispatchSavedSearchContext(ctx context.Context, name string,
+	opts *DispatchOptions) (string, error) {
+
+	u, err := c.buildEntityPath(c.servicesPath("saved", "searches"), name,
+		"dispatch")
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.makeRestRequestContext(ctx, http.MethodPost, u,
+		c.outputModeParams(opts.params()))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err = checkResponse(resp); err != nil {
+		return "", err
+	}
+
+	result := struct {
+		SID string `xml:"sid" json:"sid"`
+	}{}
+	if c.OutputMode == OutputModeJSON {
+		err = json.NewDecoder(resp.Body).Decode(&result)
+	} else {
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return result.SID, nil
+}
+
+// SavedSearchHistory returns the jobs that have been dispatched for a saved
+// search.
+func (c *Client) SavedSearchHistory(name string) ([]*SavedSearchJob, error) {
+	return c.SavedSearchHistoryContext(context.Background(), name)
+}
+
+// SavedSearchHistoryContext is SavedSearchHistory with a context that cancels
+// the request.
+func (c *Client) SavedSearchHistoryContext(ctx context.Context,
+	name string) ([]*SavedSearchJob, error) {
+
+	u, err := c.buildEntityPath(c.servicesPath("saved", "searches"), name,
+		"history")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.entityURLRequestContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*SavedSearchJob{}
+	for _, entry := range resp.Entries {
+		content := entry.Contents.Map()
+		result = append(result, &SavedSearchJob{
+			SID:              entry.Title,
+			Updated:          entry.Updated,
+			IsDone:           parseSplunkBool(content["isDone"]),
+			IsFailed:         parseSplunkBool(content["isFailed"]),
+			IsScheduled:      parseSplunkBool(content["isScheduled"]),
+			IsRealTimeSearch: parseSplunkBool(content["isRealTimeSearch"]),
+			IsZombie:         parseSplunkBool(content["isZombie"]),
+			Content:          content,
+		})
+	}
+	return result, nil
+}
+
+func firstSavedSearch(resp *RestResponse) (*SavedSearch, error) {
+	if len(resp.Entries) == 0 {
+		return nil, errors.New("No saved search returned.")
+	}
+	return newSavedSearchFromEntry(resp.Entries[0]), nil
+}
+
+func newSavedSearchFromEntry(entry RestEntry) *SavedSearch {
+	content := entry.Contents.Map()
+	severity, _ := strconv.Atoi(content["alert.severity"])
+
+	result := &SavedSearch{
+		Name:                entry.Title,
+		ID:                  entry.ID,
+		Search:              content["search"],
+		Description:         content["description"],
+		Disabled:            parseSplunkBool(content["disabled"]),
+		IsScheduled:         parseSplunkBool(content["is_scheduled"]),
+		CronSchedule:        content["cron_schedule"],
+		EarliestTime:        content["dispatch.earliest_time"],
+		LatestTime:          content["dispatch.latest_time"],
+		AlertType:           content["alert_type"],
+		AlertComparator:     content["alert_comparator"],
+		AlertThreshold:      content["alert_threshold"],
+		AlertCondition:      content["alert_condition"],
+		AlertSeverity:       severity,
+		AlertSuppress:       parseSplunkBool(content["alert.suppress"]),
+		AlertSuppressPeriod: content["alert.suppress.period"],
+		AlertTrack:          parseSplunkBool(content["alert.track"]),
+		Actions:             content["actions"],
+		Settings:            make(map[string]string),
+		Content:             content,
+	}
+
+	for name, value := range content {
+		if savedSearchFields[name] {
+			continue
+		}
+		for _, prefix := range savedSearchSettingPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				result.Settings[name] = value
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// params returns the writable settings of the saved search as form values.
+// Empty strings are not sent so that Splunk keeps its defaults.
+func (search *SavedSearch) params() url.Values {
+	params := url.Values{}
+	for name, value := range search.Settings {
+		params.Set(name, value)
+	}
+
+	setIfNotEmpty := func(name, value string) {
+		if len(value) > 0 {
+			params.Set(name, value)
+		}
+	}
+
+	params.Set("search", search.Search)
+	params.Set("disabled", strconv.FormatBool(search.Disabled))
+	params.Set("is_scheduled", strconv.FormatBool(search.IsScheduled))
+	params.Set("alert.suppress", strconv.FormatBool(search.AlertSuppress))
+	params.Set("alert.track", strconv.FormatBool(search.AlertTrack))
+	setIfNotEmpty("description", search.Description)
+	setIfNotEmpty("cron_schedule", search.CronSchedule)
+	setIfNotEmpty("dispatch.earliest_time", search.EarliestTime)
+	setIfNotEmpty("dispatch.latest_time", search.LatestTime)
+	setIfNotEmpty("alert_type", search.AlertType)
+	setIfNotEmpty("alert_comparator", search.AlertComparator)
+	setIfNotEmpty("alert_threshold", search.AlertThreshold)
+	setIfNotEmpty("alert_condition", search.AlertCondition)
+	setIfNotEmpty("alert.suppress.period", search.AlertSuppressPeriod)
+	setIfNotEmpty("actions", search.Actions)
+	if search.AlertSeverity > 0 {
+		params.Set("alert.severity", strconv.Itoa(search.AlertSeverity))
+	}
+
+	return params
+}
+
+func (opts *DispatchOptions) params() url.Values {
+	params := url.Values{}
+	if opts == nil {
+		return params
+	}
+
+	for name, value := range opts.Args {
+		params.Set("args."+name, value)
+	}
+	if len(opts.EarliestTime) > 0 {
+		params.Set("dispatch.earliest_time", opts.EarliestTime)
+	}
+	if len(opts.LatestTime) > 0 {
+		params.Set("dispatch.latest_time", opts.LatestTime)
+	}
+	if len(opts.Now) > 0 {
+		params.Set("dispatch.now", opts.Now)
+	}
+	if opts.TriggerActions {
+		params.Set("trigger_actions", "1")
+	}
+	if opts.ForceDispatch {
+		params.Set("force_dispatch", "1")
+	}
+
+	return params
+}
+
+// parseSplunkBool parses the boolean values returned by the REST API, which
+// may be 0/1 or true/false.
+func parseSplunkBool(value string) bool {
+	result, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && result
+}
@@ -0,0 +1,328 @@
+package splunk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+const savedSearchResponse string = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+  <title>savedsearches</title>
+  <id>https://localhost:8089/services/saved/searches</id>
+  <updated>2016-08-10T06:43:30-07:00</updated>
+  <entry>
+    <title>Errors in the last hour</title>
+    <id>https://localhost:8089/servicesNS/admin/search/saved/searches/Errors%20in%20the%20last%20hour</id>
+    <updated>2016-08-10T06:43:30-07:00</updated>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="actions">email</s:key>
+        <s:key name="action.email.to">ops@example.com</s:key>
+        <s:key name="alert.severity">4</s:key>
+        <s:key name="alert.suppress">0</s:key>
+        <s:key name="alert_comparator">greater than</s:key>
+        <s:key name="alert_threshold">10</s:key>
+        <s:key name="alert_type">number of events</s:key>
+        <s:key name="cron_schedule">*/5 * * * *</s:key>
+        <s:key name="disabled">0</s:key>
+        <s:key name="dispatch.earliest_time">-1h</s:key>
+        <s:key name="is_scheduled">1</s:key>
+        <s:key name="next_scheduled_time">2016-08-10 06:45:00 PDT</s:key>
+        <s:key name="search">error | stats count</s:key>
+      </s:dict>
+    </content>
+  </entry>
+</feed>`
+
+func TestGetSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/saved/searches/Errors in the last hour" {
+			t.Errorf("Incorrect path requested: %v", r.URL.Path)
+		}
+		w.Write([]byte(savedSearchResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+	search, err := c.GetSavedSearch("Errors in the last hour")
+	if err != nil {
+		t.Fatalf("Failed to get saved search: %v", err)
+	}
+
+	if search.Search != "error | stats count" {
+		t.Logf("Incorrect search. Expected: error | stats count Received: %v",
+			search.Search)
+		t.Fail()
+	}
+
+	if !search.IsScheduled || search.CronSchedule != "*/5 * * * *" {
+		t.Logf("Incorrect schedule returned: %v %v", search.IsScheduled,
+			search.CronSchedule)
+		t.Fail()
+	}
+
+	if search.AlertSeverity != 4 || search.AlertType != "number of events" {
+		t.Logf("Incorrect alert settings returned: %v %v", search.AlertSeverity,
+			search.AlertType)
+		t.Fail()
+	}
+
+	if search.Settings["action.email.to"] != "ops@example.com" {
+		t.Logf("Expected action.email.to in settings: %v", search.Settings)
+		t.Fail()
+	}
+
+	if _, ok := search.Settings["next_scheduled_time"]; ok {
+		t.Log("Read only next_scheduled_time should not be in settings")
+		t.Fail()
+	}
+}
+
+func TestDispatchSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost ||
+			r.URL.Path != "/servicesNS/nobody/search/saved/searches/errors/dispatch" {
+			t.Errorf("Incorrect request: %v %v", r.Method, r.URL.Path)
+		}
+
+		r.ParseForm()
+		if r.PostForm.Get("args.host") != "web01" ||
+			r.PostForm.Get("dispatch.earliest_time") != "-15m" {
+			t.Errorf("Incorrect dispatch arguments: %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<response><sid>admin__admin__search__errors_at_1470836610_5</sid></response>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	sid, err := c.DispatchSavedSearch("errors", &DispatchOptions{
+		Args:         map[string]string{"host": "web01"},
+		EarliestTime: "-15m",
+	})
+	if err != nil {
+		t.Fatalf("Failed to dispatch saved search: %v", err)
+	}
+
+	if sid != "admin__admin__search__errors_at_1470836610_5" {
+		t.Logf("Incorrect sid returned: %v", sid)
+		t.Fail()
+	}
+}
+
+func TestListSavedSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/saved/searches" ||
+			r.URL.Query().Get("count") != "0" {
+			t.Errorf("Incorrect request: %v?%v", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Write([]byte(savedSearchResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	searches, err := c.ListSavedSearches()
+	if err != nil {
+		t.Fatalf("Failed to list saved searches: %v", err)
+	}
+
+	if len(searches) != 1 || searches[0].Name != "Errors in the last hour" {
+		t.Logf("Incorrect saved searches returned: %v", searches)
+		t.Fail()
+	}
+}
+
+func TestCreateSavedSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost ||
+			r.URL.Path != "/servicesNS/nobody/search/saved/searches" {
+			t.Errorf("Incorrect request: %v %v", r.Method, r.URL.Path)
+		}
+
+		r.ParseForm()
+		expected := url.Values{
+			"name":                   {"Errors in the last hour"},
+			"search":                 {"error | stats count"},
+			"disabled":               {"false"},
+			"is_scheduled":           {"true"},
+			"cron_schedule":          {"*/5 * * * *"},
+			"dispatch.earliest_time": {"-1h"},
+			"alert.severity":         {"4"},
+			"alert.suppress":         {"false"},
+			"alert.track":            {"false"},
+			"action.email.to":        {"ops@example.com"},
+		}
+		if !reflect.DeepEqual(r.PostForm, expected) {
+			t.Errorf("Incorrect settings posted.\nExpected: %v\nReceived: %v",
+				expected, r.PostForm)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(savedSearchResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	search, err := c.CreateSavedSearch(&SavedSearch{
+		Name:          "Errors in the last hour",
+		Search:        "error | stats count",
+		IsScheduled:   true,
+		CronSchedule:  "*/5 * * * *",
+		EarliestTime:  "-1h",
+		AlertSeverity: 4,
+		Settings:      map[string]string{"action.email.to": "ops@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create saved search: %v", err)
+	}
+
+	if search.Name != "Errors in the last hour" {
+		t.Logf("Incorrect saved search returned: %v", search.Name)
+		t.Fail()
+	}
+
+	_, err = c.CreateSavedSearch(&SavedSearch{Search: "index=main"})
+	if err == nil {
+		t.Log("Expected an error for a saved search without a name.")
+		t.Fail()
+	}
+}
+
+func TestUpdateAndDeleteSavedSearch(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		r.ParseForm()
+		if r.Method == http.MethodPost {
+			if len(r.PostForm.Get("name")) > 0 {
+				t.Errorf("Name should not be posted when updating: %v", r.PostForm)
+			}
+			if r.PostForm.Get("search") != "error | stats count by host" {
+				t.Errorf("Incorrect search posted: %v", r.PostForm)
+			}
+			w.Write([]byte(savedSearchResponse))
+			return
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	_, err := c.UpdateSavedSearch(&SavedSearch{
+		Name:   "errors",
+		Search: "error | stats count by host",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update saved search: %v", err)
+	}
+
+	if err = c.DeleteSavedSearch("errors"); err != nil {
+		t.Fatalf("Failed to delete saved search: %v", err)
+	}
+
+	expected := []string{
+		"POST /servicesNS/nobody/search/saved/searches/errors",
+		"DELETE /servicesNS/nobody/search/saved/searches/errors",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+		t.Fail()
+	}
+}
+
+func TestSavedSearchHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/saved/searches/errors/history" {
+			t.Errorf("Incorrect path requested: %v", r.URL.Path)
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:s="http://dev.splunk.com/ns/rest">
+  <entry>
+    <title>scheduler__admin__search__errors_at_1470836400_12</title>
+    <updated>2016-08-10T06:40:00-07:00</updated>
+    <content type="text/xml">
+      <s:dict>
+        <s:key name="isDone">1</s:key>
+        <s:key name="isFailed">0</s:key>
+        <s:key name="isScheduled">1</s:key>
+        <s:key name="isRealTimeSearch">0</s:key>
+        <s:key name="isZombie">0</s:key>
+      </s:dict>
+    </content>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	jobs, err := c.SavedSearchHistory("errors")
+	if err != nil {
+		t.Fatalf("Failed to get saved search history: %v", err)
+	}
+
+	if len(jobs) != 1 {
+		t.Fatalf("Expected one job, received: %v", len(jobs))
+	}
+
+	job := jobs[0]
+	if job.SID != "scheduler__admin__search__errors_at_1470836400_12" ||
+		job.Updated != "2016-08-10T06:40:00-07:00" {
+		t.Logf("Incorrect job returned: %+v", job)
+		t.Fail()
+	}
+
+	if !job.IsDone || job.IsFailed || !job.IsScheduled || job.IsRealTimeSearch || job.IsZombie {
+		t.Logf("Incorrect job state returned: %+v", job)
+		t.Fail()
+	}
+}
+
+func TestSavedSearchNameEscaping(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+
+		if r.URL.Path == "/servicesNS/nobody/search/saved/searches/Errors/hour #1/dispatch" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`<response><sid>errors_hour</sid></response>`))
+			return
+		}
+		w.Write([]byte(savedSearchResponse))
+	}))
+	defer server.Close()
+
+	name := "Errors/hour #1"
+	c := &Client{BaseURL: server.URL, Owner: "nobody", Namespace: "search"}
+	if _, err := c.GetSavedSearch(name); err != nil {
+		t.Fatalf("Failed to get saved search: %v", err)
+	}
+	if _, err := c.UpdateSavedSearch(&SavedSearch{Name: name, Search: "error"}); err != nil {
+		t.Fatalf("Failed to update saved search: %v", err)
+	}
+	if err := c.DeleteSavedSearch(name); err != nil {
+		t.Fatalf("Failed to delete saved search: %v", err)
+	}
+	if _, err := c.DispatchSavedSearch(name, nil); err != nil {
+		t.Fatalf("Failed to dispatch saved search: %v", err)
+	}
+	if _, err := c.SavedSearchHistory(name); err != nil {
+		t.Fatalf("Failed to get saved search history: %v", err)
+	}
+
+	path := "/servicesNS/nobody/search/saved/searches/Errors%2Fhour%20%231"
+	expected := []string{
+		"GET " + path,
+		"POST " + path,
+		"DELETE " + path,
+		"POST " + path + "/dispatch",
+		"GET " + path + "/history",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Logf("Incorrect requests.\nExpected: %v\nReceived: %v", expected, requests)
+		t.Fail()
+	}
+}
@@ -0,0 +1,150 @@
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParsedSearch is the result of parsing an SPL string with the search/parser
+// endpoint.
+type ParsedSearch struct {
+	RemoteSearch      string          `json:"remoteSearch"`
+	RemoteTimeOrdered bool            `json:"remoteTimeOrdered"`
+	EventsSearch      string          `json:"eventsSearch"`
+	EventsTimeOrdered bool            `json:"eventsTimeOrdered"`
+	EventsStreaming   bool            `json:"eventsStreaming"`
+	ReportsSearch     string          `json:"reportsSearch"`
+	CanSummarize      bool            `json:"canSummarize"`
+	Commands          []SearchCommand `json:"commands"`
+}
+
+// SearchCommand is a single command in the pipeline of a parsed search.
+type SearchCommand struct {
+	Command      string `json:"command"`
+	RawArgs      string `json:"rawargs"`
+	Pipeline     string `json:"pipeline"`
+	IsGenerating bool   `json:"isGenerating"`
+	StreamType   string `json:"streamType"`
+
+	// Args are the parsed arguments of the command.  Their structure depends on
+	// the command so they are left for the caller to decode.
+	Args json.RawMessage `json:"args"`
+}
+
+// SearchParseError is returned when Splunk is unable to parse a search.
+type SearchParseError struct {
+	Query    string
+	Messages []SearchParseMessage
+
+	// APIError has the status, method and URL of the failed request.
+	APIError *APIError
+}
+
+// SearchParseMessage is a message returned by the search parser.  Position is
+// the offset in the query that the message refers to, or -1 if Splunk didn't
+// report one.
+type SearchParseMessage struct {
+	Type     string
+	Text     string
+	Position int
+}
+
+func (err *SearchParseError) Error() string {
+	texts := []string{}
+	for _, msg := range err.Messages {
+		texts = append(texts, msg.Text)
+	}
+	return "Unable to parse search: " + strings.Join(texts, " ")
+}
+
+// Unwrap returns the APIError so that errors.As finds it.
+func (err *SearchParseError) Unwrap() error {
+	if err.APIError == nil {
+		return nil
+	}
+	return err.APIError
+}
+
+var parsePositionRegex = regexp.MustCompile(`position '?(\d+)'?`)
+
+// ParseSearch validates and decomposes an SPL string using the search/parser
+// endpoint without dispatching it.  If the search is invalid the error is a
+// *SearchParseError.
+func (c *Client) ParseSearch(query string, parseOnly bool) (*ParsedSearch, error) {
+	return c.ParseSearchContext(context.Background(), query, parseOnly)
+}
+
+// ParseSearchContext is ParseSearch with a context that cancels the request.
+func (c *Client) ParseSearchContext(ctx context.Context, query string,
+	parseOnly bool) (*ParsedSearch, error) {
+
+	u, err := c.buildRequestPath(c.servicesPath("search", "parser"))
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("parse_only", strconv.FormatBool(parseOnly))
+	params.Set("output_mode", "json")
+
+	resp, err := c.makeRestRequestContext(ctx, http.MethodGet, u, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, decodeSearchParseError(query, resp)
+	}
+
+	if err = checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := &ParsedSearch{}
+	decoder := json.NewDecoder(resp.Body)
+	err = decoder.Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func decodeSearchParseError(query string, resp *http.Response) error {
+	body := struct {
+		Messages []RestMessage `json:"messages"`
+	}{}
+
+	decoder := json.NewDecoder(resp.Body)
+	err := decoder.Decode(&body)
+	if err != nil || len(body.Messages) == 0 {
+		return newAPIError(resp, body.Messages)
+	}
+
+	result := &SearchParseError{
+		Query:    query,
+		APIError: newAPIError(resp, body.Messages),
+	}
+	for _, msg := range body.Messages {
+		position := -1
+		match := parsePositionRegex.FindStringSubmatch(msg.Text)
+		if match != nil {
+			position, _ = strconv.Atoi(match[1])
+		}
+
+		result.Messages = append(result.Messages, SearchParseMessage{
+			Type:     msg.Type,
+			Text:     msg.Text,
+			Position: position,
+		})
+	}
+
+	return result
+}
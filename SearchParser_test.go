@@ -0,0 +1,81 @@
+package splunk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const searchParserResponse = `{
+  "remoteSearch": "litsearch index=main | fields keepcolorder=t \"*\"",
+  "remoteTimeOrdered": true,
+  "eventsSearch": "search index=main",
+  "eventsTimeOrdered": true,
+  "eventsStreaming": true,
+  "reportsSearch": "stats count by host",
+  "canSummarize": true,
+  "commands": [
+    {"command": "search", "rawargs": "index=main", "pipeline": "streaming",
+     "args": {"search": ["index=main"]}, "isGenerating": true, "streamType": "SP_STREAM"},
+    {"command": "stats", "rawargs": "count by host", "pipeline": "report",
+     "args": {"stat-specifiers": [{"function": "count"}]}, "isGenerating": false, "streamType": "SP_EVENTS"}
+  ]
+}`
+
+const searchParserErrorResponse = `{"messages":[{"type":"FATAL",
+"text":"Error in 'SearchParser': Missing a search command before '|'. Error at position '0' of search query '| stats count'."}]}`
+
+func TestParseSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/search/parser" {
+			t.Errorf("Incorrect path requested: %v", r.URL.Path)
+		}
+		if r.URL.Query().Get("output_mode") != "json" {
+			t.Errorf("Expected output_mode=json, received: %v", r.URL.RawQuery)
+		}
+
+		if r.URL.Query().Get("q") == "| stats count" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(searchParserErrorResponse))
+			return
+		}
+		w.Write([]byte(searchParserResponse))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL}
+
+	result, err := c.ParseSearch("index=main | stats count by host", true)
+	if err != nil {
+		t.Fatalf("Failed to parse search: %v", err)
+	}
+
+	if len(result.Commands) != 2 || result.Commands[1].Command != "stats" {
+		t.Logf("Incorrect commands returned: %v", result.Commands)
+		t.Fail()
+	}
+
+	if result.Commands[0].RawArgs != "index=main" {
+		t.Logf("Incorrect raw args. Expected: index=main Received: %v",
+			result.Commands[0].RawArgs)
+		t.Fail()
+	}
+
+	_, err = c.ParseSearch("| stats count", true)
+	parseErr, ok := err.(*SearchParseError)
+	if !ok {
+		t.Fatalf("Expected a SearchParseError, received: %v", err)
+	}
+
+	if parseErr.Messages[0].Position != 0 || parseErr.Messages[0].Type != "FATAL" {
+		t.Logf("Incorrect parse message returned: %v", parseErr.Messages[0])
+		t.Fail()
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest ||
+		apiErr.Method != http.MethodGet {
+		t.Logf("Expected the APIError to be unwrapped, received: %+v", apiErr)
+		t.Fail()
+	}
+}
@@ -0,0 +1,62 @@
+package splunk
+
+import "context"
+
+// Login logs in with username and password and sets the client's session key.
+// Use it instead of NewClientFromLogin to log in with a client that has its
+// HTTPClient or HTTPOptions configured.  The credentials are kept so that the
+// session key is renewed when Splunk rejects it as expired.
+func (c *Client) Login(username, password string) error {
+	return c.LoginContext(context.Background(), username, password)
+}
+
+// LoginContext is Login with a context that cancels the login request.
+func (c *Client) LoginContext(ctx context.Context, username, password string) error {
+	key, err := c.getSessionKeyContext(ctx, username, password)
+	if err != nil {
+		return err
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.SessionKey = key.SessionKey
+	c.username = username
+	c.password = password
+	return nil
+}
+
+func (c *Client) currentSessionKey() string {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.SessionKey
+}
+
+// canRenewSessionKey returns true if the client authenticates with a session
+// key and has credentials from Login.
+func (c *Client) canRenewSessionKey() bool {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.Authenticator == nil && len(c.username) > 0
+}
+
+// renewSessionKey logs in again to replace the session key rejected, which
+// is the key that a failed request was sent with.  When several requests fail
+// together only the first logs in, the others find that the key has already
+// been replaced and use the new one.
+func (c *Client) renewSessionKey(ctx context.Context, rejected string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.SessionKey != rejected {
+		return nil
+	}
+
+	key, err := c.getSessionKeyContext(ctx, c.username, c.password)
+	if err != nil {
+		return err
+	}
+
+	c.SessionKey = key.SessionKey
+	return nil
+}
@@ -0,0 +1,141 @@
+package splunk
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// sessionServer issues a new session key on each login and only accepts the
+// most recent one.
+type sessionServer struct {
+	mu      sync.Mutex
+	logins  int
+	current string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/"+login_endpoint {
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("password") != "changeme" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`<response><messages><msg type="WARN">Login failed</msg></messages></response>`))
+			return
+		}
+		s.logins++
+		s.current = fmt.Sprintf("key%v", s.logins)
+		fmt.Fprintf(w, "<response><sessionKey>%v</sessionKey></response>", s.current)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Splunk "+s.current {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`<response><messages><msg type="WARN">call not properly authenticated</msg></messages></response>`))
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if r.Method == http.MethodPost && string(body) != "name=fitness" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>fitness</title></entry></feed>`))
+}
+
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = "expired"
+}
+
+func TestSessionKeyRenewedOnUnauthorized(t *testing.T) {
+	sessions := &sessionServer{}
+	server := httptest.NewServer(sessions)
+	defer server.Close()
+
+	c, err := NewClientFromLogin("admin", "changeme", "", "", server.URL, false)
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+
+	sessions.expire()
+
+	_, err = c.CreateEntity([]string{"services", "data", "indexes"},
+		url.Values{"name": {"fitness"}})
+	if err != nil {
+		t.Fatalf("Request was not retried after logging in again: %v", err)
+	}
+
+	if c.SessionKey != "key2" || sessions.logins != 2 {
+		t.Logf("Expected a second login, session key: %v logins: %v",
+			c.SessionKey, sessions.logins)
+		t.Fail()
+	}
+}
+
+func TestSessionKeyRenewedOnceForConcurrentRequests(t *testing.T) {
+	sessions := &sessionServer{}
+	server := httptest.NewServer(sessions)
+	defer server.Close()
+
+	c, err := NewClientFromLogin("admin", "changeme", "", "", server.URL, false)
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+
+	sessions.expire()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetEntities([]string{"services", "data", "indexes"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Logf("Concurrent request failed: %v", err)
+			t.Fail()
+		}
+	}
+
+	if sessions.logins != 2 {
+		t.Logf("Expected one login to renew the session key, logins: %v", sessions.logins)
+		t.Fail()
+	}
+}
+
+func TestSessionKeyNotRenewedWithoutCredentials(t *testing.T) {
+	sessions := &sessionServer{current: "key0"}
+	server := httptest.NewServer(sessions)
+	defer server.Close()
+
+	c := NewClientFromSessionKey("expired", "", "", server.URL, false)
+	_, err := c.GetEntities([]string{"services", "data", "indexes"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Logf("Expected an unauthorized APIError, received: %v", err)
+		t.Fail()
+	}
+
+	if sessions.logins != 0 {
+		t.Logf("Client without credentials should not log in, logins: %v", sessions.logins)
+		t.Fail()
+	}
+}
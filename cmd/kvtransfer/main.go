@@ -0,0 +1,178 @@
+// Command kvtransfer exports a KV Store collection to NDJSON or CSV and imports
+// it back, for moving collections between Splunk environments.
+//
+//	kvtransfer -export -app fitness -collection fitbit_tokens -file tokens.ndjson
+//	kvtransfer -import -app fitness -collection fitbit_tokens -file tokens.ndjson \
+//		-resume tokens.resume
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+	"github.com/AndyNortrup/GoSplunk/kvstore"
+)
+
+func main() {
+	export := flag.Bool("export", false, "Export the collection to the file.")
+	imp := flag.Bool("import", false, "Import the file into the collection.")
+	baseURL := flag.String("url", splunk.LocalSplunkMgmntURL, "Splunk management URL.")
+	sessionKey := flag.String("session-key", "", "Session key, instead of username and password.")
+	token := flag.String("token", "",
+		"Authentication token, instead of username and password, defaults to $SPLUNK_TOKEN.")
+	username := flag.String("username", "", "Splunk username.")
+	password := flag.String("password", "", "Splunk password, defaults to $SPLUNK_PASSWORD.")
+	app := flag.String("app", "", "App that owns the collection.")
+	owner := flag.String("owner", "nobody", "Owner of the collection.")
+	validateTLS := flag.Bool("validate-tls", false, "Validate the server certificate.")
+	caFile := flag.String("ca-file", "",
+		"PEM file of CAs that sign the server certificate, implies -validate-tls.")
+	serverName := flag.String("server-name", "",
+		"Name to verify the server certificate against, implies -validate-tls.")
+	certFile := flag.String("cert", "", "Client certificate for mutual TLS.")
+	keyFile := flag.String("key", "", "Private key of the client certificate.")
+	collection := flag.String("collection", "", "Name of the collection.")
+	file := flag.String("file", "", "File to export to or import from, - for stdout or stdin.")
+	format := flag.String("format", kvstore.FormatNDJSON, "File format, ndjson or csv.")
+	chunkSize := flag.Int("chunk", kvstore.DefaultChunkSize, "Records per request.")
+	resume := flag.String("resume", "", "Import progress file used to resume a failed import.")
+	flag.Parse()
+
+	// Secrets are read from the environment after parsing so that they aren't
+	// printed as flag defaults in the usage message.
+	if len(*password) == 0 {
+		*password = os.Getenv("SPLUNK_PASSWORD")
+	}
+	if len(*token) == 0 {
+		*token = os.Getenv("SPLUNK_TOKEN")
+	}
+
+	if *export == *imp {
+		log.Fatal("Specify one of -export or -import.")
+	}
+	if len(*app) == 0 || len(*collection) == 0 || len(*file) == 0 {
+		log.Fatal("-app, -collection and -file are required.")
+	}
+
+	// A CA bundle or server name is only used when the certificate is verified.
+	if len(*caFile) > 0 || len(*serverName) > 0 {
+		*validateTLS = true
+	}
+
+	client := splunk.NewClientFromSessionKey(*sessionKey, *app, *owner,
+		*baseURL, *validateTLS)
+	if len(*token) > 0 {
+		client.Authenticator = splunk.TokenAuth(*token)
+	}
+
+	if len(*caFile) > 0 {
+		pool, err := splunk.LoadCABundle(*caFile)
+		if err != nil {
+			log.Fatalf("Unable to load CA bundle: %v", err)
+		}
+		client.HTTPOptions.RootCAs = pool
+	}
+	client.HTTPOptions.ServerName = *serverName
+	if len(*certFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			log.Fatalf("Unable to load client certificate: %v", err)
+		}
+		client.HTTPOptions.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(*sessionKey) == 0 && len(*token) == 0 {
+		err := client.Login(*username, *password)
+		if err != nil {
+			log.Fatalf("Unable to log in to Splunk: %v", err)
+		}
+	}
+
+	if *export {
+		runExport(client, *collection, *file, kvstore.Format(*format), *chunkSize)
+	} else {
+		runImport(client, *collection, *file, kvstore.Format(*format), *chunkSize, *resume)
+	}
+}
+
+func runExport(client *splunk.Client, collection, file string,
+	format kvstore.Format, pageSize int) {
+
+	out := os.Stdout
+	if file != "-" {
+		var err error
+		out, err = os.Create(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	count, err := kvstore.Export(client, collection, out, kvstore.ExportOptions{
+		Format:   format,
+		PageSize: pageSize,
+		Progress: func(exported int) { log.Printf("Exported %v records", exported) },
+	})
+	if err != nil {
+		log.Fatalf("Export failed after %v records: %v", count, err)
+	}
+	log.Printf("Exported %v records from %v", count, collection)
+}
+
+func runImport(client *splunk.Client, collection, file string,
+	format kvstore.Format, chunkSize int, resume string) {
+
+	in := os.Stdin
+	if file != "-" {
+		var err error
+		in, err = os.Open(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer in.Close()
+	}
+
+	skip := 0
+	if len(resume) > 0 {
+		b, err := ioutil.ReadFile(resume)
+		if err == nil {
+			skip, err = strconv.Atoi(strings.TrimSpace(string(b)))
+			if err != nil {
+				log.Fatalf("Unable to read resume file %v: %v", resume, err)
+			}
+			log.Printf("Resuming import after %v records", skip)
+		} else if !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+	}
+
+	count, err := kvstore.Import(client, collection, in, kvstore.ImportOptions{
+		Format:    format,
+		ChunkSize: chunkSize,
+		Skip:      skip,
+		Progress: func(imported int) {
+			log.Printf("Imported %v records", imported)
+			if len(resume) > 0 {
+				err := ioutil.WriteFile(resume, []byte(strconv.Itoa(imported)), 0644)
+				if err != nil {
+					log.Printf("Unable to write resume file %v: %v", resume, err)
+				}
+			}
+		},
+	})
+	if err != nil {
+		log.Fatalf("Import failed after %v records, rerun with -resume to continue: %v",
+			count, err)
+	}
+
+	if len(resume) > 0 {
+		os.Remove(resume)
+	}
+	log.Printf("Imported %v records into %v", count, collection)
+}
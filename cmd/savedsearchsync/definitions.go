@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+	"gopkg.in/yaml.v3"
+)
+
+// readDefinitionsFile reads saved search definitions from a YAML file, or a
+// JSON file when it ends in .json.  - reads YAML, which includes JSON, from
+// stdin.
+func readDefinitionsFile(file string) ([]*splunk.SavedSearch, error) {
+	if file == "-" {
+		return readDefinitionsYAML(os.Stdin)
+	}
+
+	in, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return splunk.ReadSavedSearchDefinitions(in)
+	}
+	return readDefinitionsYAML(in)
+}
+
+// readDefinitionsYAML decodes a YAML list of saved search definitions, each a
+// mapping with the same keys as splunk.ReadSavedSearchDefinitions, e.g. name,
+// search and cron_schedule.  Numbers and booleans are accepted for settings
+// that Splunk stores as strings, such as alert_threshold.
+func readDefinitionsYAML(r io.Reader) ([]*splunk.SavedSearch, error) {
+	result := []*splunk.SavedSearch{}
+	err := yaml.NewDecoder(r).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, search := range result {
+		if len(search.Name) == 0 {
+			return nil, errors.New("Saved search definition is missing a name.")
+		}
+	}
+	return result, nil
+}
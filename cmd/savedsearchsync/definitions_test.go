@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+const definitionsYAML = `
+- name: errors
+  search: error | stats count
+  is_scheduled: true
+  cron_schedule: "*/10 * * * *"
+- name: unchanged
+  search: index=main
+`
+
+const definitionsJSON = `[
+  {"name": "errors", "search": "error | stats count", "is_scheduled": true,
+   "cron_schedule": "*/10 * * * *"},
+  {"name": "unchanged", "search": "index=main"}
+]`
+
+func TestReadDefinitionsYAML(t *testing.T) {
+	fromYAML, err := readDefinitionsYAML(strings.NewReader(definitionsYAML))
+	if err != nil {
+		t.Fatalf("Failed to read YAML definitions: %v", err)
+	}
+
+	fromJSON, err := splunk.ReadSavedSearchDefinitions(strings.NewReader(definitionsJSON))
+	if err != nil {
+		t.Fatalf("Failed to read JSON definitions: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Logf("YAML definitions differ from JSON.\nYAML: %+v\nJSON: %+v",
+			fromYAML[0], fromJSON[0])
+		t.Fail()
+	}
+
+	_, err = readDefinitionsYAML(strings.NewReader("- search: index=main\n"))
+	if err == nil {
+		t.Log("Expected an error for a definition without a name.")
+		t.Fail()
+	}
+}
+
+func TestReadDefinitionsYAMLScalars(t *testing.T) {
+	definitions, err := readDefinitionsYAML(strings.NewReader(`
+- name: errors
+  search: error | stats count
+  is_scheduled: true
+  alert_threshold: 5
+  alert.severity: 4
+  settings:
+    counttype: 5
+    action.email: true
+`))
+	if err != nil {
+		t.Fatalf("Failed to read YAML definitions with numbers: %v", err)
+	}
+
+	search := definitions[0]
+	if !search.IsScheduled || search.AlertThreshold != "5" || search.AlertSeverity != 4 ||
+		search.Settings["counttype"] != "5" || search.Settings["action.email"] != "true" {
+		t.Logf("Incorrect YAML scalars decoded: %+v", search)
+		t.Fail()
+	}
+}
@@ -0,0 +1,107 @@
+// Command savedsearchsync reconciles the saved searches in an app with
+// definitions in a YAML or JSON file.  It prints the plan and only makes the
+// changes with -apply.
+//
+//	savedsearchsync -app ops -file searches.yaml -prune
+//	savedsearchsync -app ops -file searches.yaml -prune -apply
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "Make the changes instead of only printing the plan.")
+	prune := flag.Bool("prune", false, "Delete saved searches in the app that are not defined.")
+	baseURL := flag.String("url", splunk.LocalSplunkMgmntURL, "Splunk management URL.")
+	sessionKey := flag.String("session-key", "", "Session key, instead of username and password.")
+	token := flag.String("token", "",
+		"Authentication token, instead of username and password, defaults to $SPLUNK_TOKEN.")
+	username := flag.String("username", "", "Splunk username.")
+	password := flag.String("password", "", "Splunk password, defaults to $SPLUNK_PASSWORD.")
+	app := flag.String("app", "", "App that owns the saved searches.")
+	owner := flag.String("owner", "nobody", "Owner of the saved searches.")
+	validateTLS := flag.Bool("validate-tls", false, "Validate the server certificate.")
+	caFile := flag.String("ca-file", "",
+		"PEM file of CAs that sign the server certificate, implies -validate-tls.")
+	serverName := flag.String("server-name", "",
+		"Name to verify the server certificate against, implies -validate-tls.")
+	certFile := flag.String("cert", "", "Client certificate for mutual TLS.")
+	keyFile := flag.String("key", "", "Private key of the client certificate.")
+	file := flag.String("file", "",
+		"YAML or .json file of saved search definitions, - for stdin.")
+	flag.Parse()
+
+	// Secrets are read from the environment after parsing so that they aren't
+	// printed as flag defaults in the usage message.
+	if len(*password) == 0 {
+		*password = os.Getenv("SPLUNK_PASSWORD")
+	}
+	if len(*token) == 0 {
+		*token = os.Getenv("SPLUNK_TOKEN")
+	}
+
+	if len(*app) == 0 || len(*file) == 0 {
+		log.Fatal("-app and -file are required.")
+	}
+
+	// A CA bundle or server name is only used when the certificate is verified.
+	if len(*caFile) > 0 || len(*serverName) > 0 {
+		*validateTLS = true
+	}
+
+	desired, err := readDefinitionsFile(*file)
+	if err != nil {
+		log.Fatalf("Unable to read saved search definitions: %v", err)
+	}
+
+	client := splunk.NewClientFromSessionKey(*sessionKey, *app, *owner,
+		*baseURL, *validateTLS)
+	if len(*token) > 0 {
+		client.Authenticator = splunk.TokenAuth(*token)
+	}
+
+	if len(*caFile) > 0 {
+		pool, err := splunk.LoadCABundle(*caFile)
+		if err != nil {
+			log.Fatalf("Unable to load CA bundle: %v", err)
+		}
+		client.HTTPOptions.RootCAs = pool
+	}
+	client.HTTPOptions.ServerName = *serverName
+	if len(*certFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			log.Fatalf("Unable to load client certificate: %v", err)
+		}
+		client.HTTPOptions.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(*sessionKey) == 0 && len(*token) == 0 {
+		err := client.Login(*username, *password)
+		if err != nil {
+			log.Fatalf("Unable to log in to Splunk: %v", err)
+		}
+	}
+
+	plan, err := client.PlanSavedSearches(desired, *prune)
+	if err != nil {
+		log.Fatalf("Unable to plan saved searches: %v", err)
+	}
+	fmt.Print(plan)
+
+	if !*apply || len(plan.Changes) == 0 {
+		return
+	}
+
+	if err = client.ApplySavedSearchPlan(plan); err != nil {
+		log.Fatalf("Apply failed: %v", err)
+	}
+	log.Printf("Applied %v changes to %v", len(plan.Changes), *app)
+}
@@ -0,0 +1,172 @@
+// Package kvstore provides typed access to Splunk KV Store collections.
+package kvstore
+
+import (
+	"context"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+// Handle is a KV Store collection whose records are encoded from and decoded
+// into values of type T.
+type Handle[T any] struct {
+	client *splunk.Client
+	name   string
+}
+
+// Collection creates a Handle for the collection with the given name.  The
+// client must have the namespace of the app that owns the collection.
+func Collection[T any](client *splunk.Client, name string) *Handle[T] {
+	return &Handle[T]{client: client, name: name}
+}
+
+// Name returns the name of the collection.
+func (h *Handle[T]) Name() string {
+	return h.name
+}
+
+// Get returns the record with the given key.
+func (h *Handle[T]) Get(key string) (T, error) {
+	return h.GetContext(context.Background(), key)
+}
+
+// GetContext is Get with a context that cancels the request.
+func (h *Handle[T]) GetContext(ctx context.Context, key string) (T, error) {
+	var result T
+	err := h.client.KVStoreGetContext(ctx, h.name, key, &result)
+	return result, err
+}
+
+// Insert adds a record to the collection and returns its generated key.
+func (h *Handle[T]) Insert(record T) (string, error) {
+	return h.InsertContext(context.Background(), record)
+}
+
+// InsertContext is Insert with a context that cancels the request.
+func (h *Handle[T]) InsertContext(ctx context.Context, record T) (string, error) {
+	return h.client.KVStoreInsertContext(ctx, h.name, record)
+}
+
+// Update replaces the record with the given key.
+func (h *Handle[T]) Update(key string, record T) error {
+	return h.UpdateContext(context.Background(), key, record)
+}
+
+// UpdateContext is Update with a context that cancels the request.
+func (h *Handle[T]) UpdateContext(ctx context.Context, key string, record T) error {
+	return h.client.KVStoreUpdateContext(ctx, h.name, key, record)
+}
+
+// Delete removes the record with the given key.
+func (h *Handle[T]) Delete(key string) error {
+	return h.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete with a context that cancels the request.
+func (h *Handle[T]) DeleteContext(ctx context.Context, key string) error {
+	return h.client.KVStoreDeleteContext(ctx, h.name, key)
+}
+
+// DeleteQuery removes the records that match filter.  A nil filter removes
+// every record in the collection.
+func (h *Handle[T]) DeleteQuery(filter splunk.KVStoreFilter) error {
+	return h.DeleteQueryContext(context.Background(), filter)
+}
+
+// DeleteQueryContext is DeleteQuery with a context that cancels the request.
+func (h *Handle[T]) DeleteQueryContext(ctx context.Context,
+	filter splunk.KVStoreFilter) error {
+	if filter == nil {
+		return h.client.KVStoreDeleteQueryContext(ctx, h.name, nil)
+	}
+	return h.client.KVStoreDeleteQueryContext(ctx, h.name, filter)
+}
+
+// Query returns the records that match query.  A nil query returns every
+// record in the collection.
+func (h *Handle[T]) Query(query *splunk.KVStoreQuery) ([]T, error) {
+	return h.QueryContext(context.Background(), query)
+}
+
+// QueryContext is Query with a context that cancels the request.
+func (h *Handle[T]) QueryContext(ctx context.Context,
+	query *splunk.KVStoreQuery) ([]T, error) {
+	result := []T{}
+	err := h.client.KVStoreFindContext(ctx, h.name, query, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchSave inserts or updates records in a single request and returns their
+// keys in order.
+func (h *Handle[T]) BatchSave(records []T) ([]string, error) {
+	return h.BatchSaveContext(context.Background(), records)
+}
+
+// BatchSaveContext is BatchSave with a context that cancels the request.
+func (h *Handle[T]) BatchSaveContext(ctx context.Context, records []T) ([]string, error) {
+	return h.client.KVStoreBatchSaveContext(ctx, h.name, records)
+}
+
+// Iterate returns an Iterator over the records that match query, requesting
+// pageSize records at a time.  See splunk.KVStoreIterate.
+func (h *Handle[T]) Iterate(ctx context.Context, query *splunk.KVStoreQuery,
+	pageSize int) *Iterator[T] {
+	return &Iterator[T]{it: h.client.KVStoreIterate(ctx, h.name, query, pageSize)}
+}
+
+// Iterator yields the records of a collection one at a time, paging through
+// the collection as needed.
+type Iterator[T any] struct {
+	it    *splunk.KVStoreIterator
+	value T
+	err   error
+}
+
+// Next advances to the next record and decodes it.  It returns false when
+// there are no more records or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || !it.it.Next() {
+		return false
+	}
+
+	var value T
+	if it.err = it.it.Decode(&value); it.err != nil {
+		return false
+	}
+	it.value = value
+	return true
+}
+
+// Value returns the current record.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err()
+}
+
+// UpdateFunc reads the record with the given key, applies update to it and
+// writes it back only if versionField is unchanged on the server, retrying on
+// conflict.  The updated record is returned.  See splunk.KVStoreUpdateFunc.
+func (h *Handle[T]) UpdateFunc(key, versionField string,
+	update func(record *T) error) (T, error) {
+	return h.UpdateFuncContext(context.Background(), key, versionField, update)
+}
+
+// UpdateFuncContext is UpdateFunc with a context that cancels the requests.
+func (h *Handle[T]) UpdateFuncContext(ctx context.Context, key, versionField string,
+	update func(record *T) error) (T, error) {
+
+	var record T
+	err := h.client.KVStoreUpdateFuncContext(ctx, h.name, key, versionField, &record,
+		func() error { return update(&record) })
+	return record, err
+}
@@ -0,0 +1,64 @@
+package kvstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+type user struct {
+	Key  string `json:"_key,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestCollection(t *testing.T) {
+	const collectionPath = "/servicesNS/nobody/fitness/storage/collections/data/fitbit_tokens"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == collectionPath+"/1":
+			w.Write([]byte(`{"_key":"1","name":"bob"}`))
+		case r.Method == http.MethodGet && r.URL.Path == collectionPath:
+			w.Write([]byte(`[{"_key":"1","name":"bob"},{"_key":"2","name":"alice"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == collectionPath:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"_key":"3"}`))
+		default:
+			t.Errorf("Unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &splunk.Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+	coll := Collection[user](client, "fitbit_tokens")
+
+	record, err := coll.Get("1")
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+	if record.Name != "bob" {
+		t.Logf("Incorrect record returned: %v", record)
+		t.Fail()
+	}
+
+	records, err := coll.Query(&splunk.KVStoreQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to query records: %v", err)
+	}
+	if len(records) != 2 || records[1].Name != "alice" {
+		t.Logf("Incorrect records returned: %v", records)
+		t.Fail()
+	}
+
+	key, err := coll.Insert(user{Name: "carol"})
+	if err != nil {
+		t.Fatalf("Failed to insert record: %v", err)
+	}
+	if key != "3" {
+		t.Logf("Incorrect key returned: %v", key)
+		t.Fail()
+	}
+}
@@ -0,0 +1,327 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+type Format string
+
+const FormatNDJSON = "ndjson"
+const FormatCSV = "csv"
+
+// DefaultChunkSize is the number of records read or saved per request, which
+// matches the default max_documents_per_batch_save in limits.conf.
+const DefaultChunkSize = 1000
+
+// ExportOptions control how a collection is exported.
+type ExportOptions struct {
+	Format Format
+	// PageSize is the number of records requested at a time.
+	PageSize int
+	// Fields are the columns written to a CSV export, values of other fields
+	// are left out.  If it is empty the collection is read twice, first to
+	// find every field used by any record.
+	Fields []string
+	// Progress is called with the number of records exported after each page.
+	Progress func(exported int)
+}
+
+// ImportOptions control how records are imported into a collection.
+type ImportOptions struct {
+	Format Format
+	// ChunkSize is the number of records sent in each batch_save request.
+	ChunkSize int
+	// Skip is the number of records at the start of the input that were
+	// imported previously.  Use it to resume an import that failed.
+	Skip int
+	// Progress is called with the number of records imported after each
+	// chunk, including any that were skipped.
+	Progress func(imported int)
+}
+
+// Export writes every record in a collection to w as NDJSON or CSV and
+// returns the number of records written.  Values that are not strings are
+// written to CSV as JSON.
+func Export(client *splunk.Client, collection string, w io.Writer,
+	opts ExportOptions) (int, error) {
+	return ExportContext(context.Background(), client, collection, w, opts)
+}
+
+// ExportContext is Export with a context that cancels the requests.
+func ExportContext(ctx context.Context, client *splunk.Client, collection string,
+	w io.Writer, opts ExportOptions) (int, error) {
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultChunkSize
+	}
+
+	var csvWriter *csv.Writer
+	fields := opts.Fields
+	exported := 0
+
+	if opts.Format == FormatCSV {
+		if len(fields) == 0 {
+			var err error
+			fields, err = collectionFields(ctx, client, collection, pageSize)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		// The header is written even if the collection is empty so that the
+		// file can be imported.
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(fields); err != nil {
+			return 0, err
+		}
+	}
+
+	for {
+		page, err := findPage(ctx, client, collection, exported, pageSize)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, record := range page {
+			switch opts.Format {
+			case FormatNDJSON, "":
+				compact := &bytes.Buffer{}
+				if err = json.Compact(compact, record); err != nil {
+					return exported, err
+				}
+				compact.WriteByte('\n')
+				if _, err = w.Write(compact.Bytes()); err != nil {
+					return exported, err
+				}
+
+			case FormatCSV:
+				values, err := decodeRecord(record)
+				if err != nil {
+					return exported, err
+				}
+
+				row := []string{}
+				for _, field := range fields {
+					row = append(row, csvValue(values[field]))
+				}
+				if err = csvWriter.Write(row); err != nil {
+					return exported, err
+				}
+
+			default:
+				return exported, errors.New("Unknown format: " + string(opts.Format))
+			}
+
+			exported++
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err = csvWriter.Error(); err != nil {
+				return exported, err
+			}
+		}
+
+		if opts.Progress != nil && len(page) > 0 {
+			opts.Progress(exported)
+		}
+
+		if len(page) < pageSize {
+			return exported, nil
+		}
+	}
+}
+
+// Import reads NDJSON or CSV records from r and saves them to a collection
+// with batch_save.  Records with a _key replace the existing record.  CSV
+// values are imported as strings and empty values are omitted.  The number of
+// records imported, including skipped records, is returned.
+func Import(client *splunk.Client, collection string, r io.Reader,
+	opts ImportOptions) (int, error) {
+	return ImportContext(context.Background(), client, collection, r, opts)
+}
+
+// ImportContext is Import with a context that cancels the requests.
+func ImportContext(ctx context.Context, client *splunk.Client, collection string,
+	r io.Reader, opts ImportOptions) (int, error) {
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	next, err := newRecordReader(r, opts.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	chunk := []interface{}{}
+
+	save := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		_, err := client.KVStoreBatchSaveContext(ctx, collection, chunk)
+		if err != nil {
+			return err
+		}
+		imported += len(chunk)
+		chunk = chunk[:0]
+		if opts.Progress != nil {
+			opts.Progress(imported)
+		}
+		return nil
+	}
+
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, err
+		}
+
+		if imported < opts.Skip {
+			imported++
+			continue
+		}
+
+		chunk = append(chunk, record)
+		if len(chunk) >= chunkSize {
+			if err = save(); err != nil {
+				return imported, err
+			}
+		}
+	}
+
+	return imported, save()
+}
+
+// newRecordReader returns a function that reads the next record from r until
+// it returns io.EOF.
+func newRecordReader(r io.Reader, format Format) (func() (interface{}, error), error) {
+	switch format {
+	case FormatNDJSON, "":
+		decoder := json.NewDecoder(r)
+		return func() (interface{}, error) {
+			record := json.RawMessage{}
+			err := decoder.Decode(&record)
+			return record, err
+		}, nil
+
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		header, err := reader.Read()
+		if err == io.EOF {
+			// An empty file has no records to import
+			return func() (interface{}, error) { return nil, io.EOF }, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (interface{}, error) {
+			row, err := reader.Read()
+			if err != nil {
+				return nil, err
+			}
+
+			record := make(map[string]string)
+			for i, value := range row {
+				if i < len(header) && len(value) > 0 {
+					record[header[i]] = value
+				}
+			}
+			return record, nil
+		}, nil
+	}
+
+	return nil, errors.New("Unknown format: " + string(format))
+}
+
+func decodeRecord(record json.RawMessage) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	decoder.UseNumber()
+	err := decoder.Decode(&values)
+	return values, err
+}
+
+// findPage returns pageSize records from a collection starting at skip, in
+// _key order so that pages are stable.
+func findPage(ctx context.Context, client *splunk.Client, collection string,
+	skip, pageSize int) ([]json.RawMessage, error) {
+
+	page := []json.RawMessage{}
+	err := client.KVStoreFindContext(ctx, collection, &splunk.KVStoreQuery{
+		Sort:  []splunk.KVStoreSort{{Field: "_key"}},
+		Skip:  skip,
+		Limit: pageSize,
+	}, &page)
+	return page, err
+}
+
+// collectionFields reads every record in a collection and returns the fields
+// they use sorted by name with _key first.
+func collectionFields(ctx context.Context, client *splunk.Client, collection string,
+	pageSize int) ([]string, error) {
+
+	names := make(map[string]bool)
+	for skip := 0; ; skip += pageSize {
+		page, err := findPage(ctx, client, collection, skip, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range page {
+			values, err := decodeRecord(record)
+			if err != nil {
+				return nil, err
+			}
+			for field := range values {
+				names[field] = true
+			}
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	fields := []string{}
+	for field := range names {
+		if field != "_key" && field != "_user" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return append([]string{"_key"}, fields...), nil
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	b, _ := json.Marshal(value)
+	return string(b)
+}
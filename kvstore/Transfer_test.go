@@ -0,0 +1,188 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	splunk "github.com/AndyNortrup/GoSplunk"
+)
+
+var transferRecords = []string{
+	`{"_key":"1","name":"bob","age":30}`,
+	`{"_key":"2","name":"alice","age":41}`,
+	`{"_key":"3","name":"carol","active":true}`,
+}
+
+func newTransferServer(t *testing.T, saved *[][]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if !strings.HasSuffix(r.URL.Path, "/batch_save") {
+				t.Errorf("Expected a batch_save request: %v", r.URL.Path)
+			}
+			records := []map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&records)
+			*saved = append(*saved, records)
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		if r.URL.Query().Get("sort") != "_key:1" {
+			t.Errorf("Expected a stable sort: %v", r.URL.RawQuery)
+		}
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := skip + limit
+		if end > len(transferRecords) {
+			end = len(transferRecords)
+		}
+		w.Write([]byte("[" + strings.Join(transferRecords[skip:end], ",") + "]"))
+	}))
+}
+
+func TestExport(t *testing.T) {
+	server := newTransferServer(t, nil)
+	defer server.Close()
+
+	client := &splunk.Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+
+	progress := []int{}
+	out := &bytes.Buffer{}
+	count, err := Export(client, "users", out, ExportOptions{
+		Format:   FormatNDJSON,
+		PageSize: 2,
+		Progress: func(exported int) { progress = append(progress, exported) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to export collection: %v", err)
+	}
+
+	expected := strings.Join(transferRecords, "\n") + "\n"
+	if count != 3 || out.String() != expected {
+		t.Logf("Incorrect NDJSON export.\nExpected:\n%v\nReceived:\n%v", expected, out)
+		t.Fail()
+	}
+	if len(progress) != 2 || progress[1] != 3 {
+		t.Logf("Incorrect progress reported: %v", progress)
+		t.Fail()
+	}
+
+	out.Reset()
+	_, err = Export(client, "users", out, ExportOptions{Format: FormatCSV, PageSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to export collection: %v", err)
+	}
+
+	// active only appears on the second page.
+	expected = "_key,active,age,name\n1,,30,bob\n2,,41,alice\n3,true,,carol\n"
+	if out.String() != expected {
+		t.Logf("Incorrect CSV export.\nExpected:\n%v\nReceived:\n%v", expected, out)
+		t.Fail()
+	}
+}
+
+func TestImport(t *testing.T) {
+	saved := [][]map[string]interface{}{}
+	server := newTransferServer(t, &saved)
+	defer server.Close()
+
+	client := &splunk.Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+
+	input := "_key,name\n1,bob\n2,alice\n3,\n4,dave\n"
+	count, err := Import(client, "users", strings.NewReader(input), ImportOptions{
+		Format:    FormatCSV,
+		ChunkSize: 2,
+		Skip:      1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to import collection: %v", err)
+	}
+
+	if count != 4 {
+		t.Logf("Incorrect import count. Expected: 4 Received: %v", count)
+		t.Fail()
+	}
+
+	if len(saved) != 2 || len(saved[0]) != 2 || len(saved[1]) != 1 {
+		t.Fatalf("Incorrect batches saved: %v", saved)
+	}
+
+	if saved[0][0]["name"] != "alice" {
+		t.Logf("Expected the first record to be skipped: %v", saved[0])
+		t.Fail()
+	}
+
+	if _, ok := saved[0][1]["name"]; ok {
+		t.Logf("Expected empty values to be omitted: %v", saved[0][1])
+		t.Fail()
+	}
+}
+
+func TestTransferContextCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := &splunk.Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+
+	_, err := ExportContext(ctx, client, "tokens", &bytes.Buffer{}, ExportOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected export to be cancelled, received: %v", err)
+		t.Fail()
+	}
+
+	_, err = ImportContext(ctx, client, "tokens",
+		strings.NewReader(strings.Join(transferRecords, "\n")), ImportOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected import to be cancelled, received: %v", err)
+		t.Fail()
+	}
+
+	if requests != 0 {
+		t.Logf("Cancelled requests reached the server: %v", requests)
+		t.Fail()
+	}
+}
+
+func TestEmptyCSVTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Nothing should be saved: %v %v", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := &splunk.Client{BaseURL: server.URL, Owner: "nobody", Namespace: "fitness"}
+
+	out := &bytes.Buffer{}
+	count, err := Export(client, "users", out, ExportOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("Failed to export collection: %v", err)
+	}
+
+	if count != 0 || out.String() != "_key\n" {
+		t.Logf("Expected only a header for an empty collection: %v %q", count, out)
+		t.Fail()
+	}
+
+	for _, input := range []string{out.String(), ""} {
+		count, err = Import(client, "users", strings.NewReader(input),
+			ImportOptions{Format: FormatCSV})
+		if err != nil || count != 0 {
+			t.Logf("Failed to import empty file %q: %v %v", input, count, err)
+			t.Fail()
+		}
+	}
+}